@@ -0,0 +1,66 @@
+package client
+
+import (
+	"io"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/promise"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// bridgeStreams pumps the streams of a container attached through a hijacked
+// connection, read from r and written to w: its output is copied to stdout
+// and stderr, demultiplexed unless it has a tty, and in is sent to it, after
+// which w is half-closed, if it can be, so that the container gets EOF on its
+// stdin. outputDone is called when the output ends, e.g. to restore the
+// terminal. bridgeStreams returns when the output ends, or when both the
+// output and the input end if waitStdin is set.
+//
+// Setting the terminal to raw mode and restoring it, wrapping stdout for the
+// Linux console and forwarding resizes are left to the caller, see hijack and
+// monitorTtySize.
+func bridgeStreams(r io.Reader, w io.Writer, in io.Reader, stdout, stderr io.Writer, tty, waitStdin bool, outputDone func()) error {
+	var receiveStdout chan error
+	if stdout != nil || stderr != nil {
+		receiveStdout = promise.Go(func() error {
+			if outputDone != nil {
+				defer outputDone()
+			}
+			_, err := stdcopy.CopyOutput(stdout, stderr, r, tty)
+			log.Debugf("[hijack] End of stdout")
+			return err
+		})
+	}
+
+	sendStdin := promise.Go(func() error {
+		if in != nil {
+			io.Copy(w, in)
+			log.Debugf("[hijack] End of stdin")
+		}
+
+		if conn, ok := w.(interface {
+			CloseWrite() error
+		}); ok {
+			if err := conn.CloseWrite(); err != nil {
+				log.Debugf("Couldn't send EOF: %s", err)
+			}
+		}
+		// Discard errors due to pipe interruption
+		return nil
+	})
+
+	if receiveStdout != nil {
+		if err := <-receiveStdout; err != nil {
+			log.Debugf("Error receiveStdout: %s", err)
+			return err
+		}
+	}
+
+	if waitStdin {
+		if err := <-sendStdin; err != nil {
+			log.Debugf("Error sendStdin: %s", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// halfCloser records what is sent to the container and whether the write
+// side of the connection was closed.
+type halfCloser struct {
+	sync.Mutex
+	bytes.Buffer
+	closed bool
+}
+
+func (c *halfCloser) Write(p []byte) (int, error) {
+	c.Lock()
+	defer c.Unlock()
+	return c.Buffer.Write(p)
+}
+
+func (c *halfCloser) CloseWrite() error {
+	c.Lock()
+	defer c.Unlock()
+	c.closed = true
+	return nil
+}
+
+func TestBridgeStreamsTty(t *testing.T) {
+	var (
+		conn   halfCloser
+		stdout bytes.Buffer
+		done   bool
+	)
+	err := bridgeStreams(strings.NewReader("\x1b[1mhello\r\n"), &conn, strings.NewReader("ls\n"), &stdout, nil, true, true, func() { done = true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "\x1b[1mhello\r\n" {
+		t.Fatalf("Unexpected output: %q", stdout.String())
+	}
+	if conn.String() != "ls\n" || !conn.closed {
+		t.Fatalf("Expected the input to be sent and followed by EOF, got %q (closed: %v)", conn.String(), conn.closed)
+	}
+	if !done {
+		t.Fatal("outputDone wasn't called")
+	}
+}
+
+func TestBridgeStreamsMultiplexed(t *testing.T) {
+	var muxed bytes.Buffer
+	stdcopy.NewStdWriter(&muxed, stdcopy.Stdout).Write([]byte("out"))
+	stdcopy.NewStdWriter(&muxed, stdcopy.Stderr).Write([]byte("err"))
+
+	var (
+		conn           halfCloser
+		stdout, stderr bytes.Buffer
+	)
+	if err := bridgeStreams(&muxed, &conn, nil, &stdout, &stderr, false, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "out" || stderr.String() != "err" {
+		t.Fatalf("Unexpected output: stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+	if !conn.closed {
+		t.Fatal("Expected EOF to be sent without input")
+	}
+}
@@ -38,7 +38,9 @@ type DockerCli struct {
 	isTerminalIn bool
 	// isTerminalOut describes if client's STDOUT is a TTY
 	isTerminalOut bool
-	transport     *http.Transport
+	// isLinuxConsole describes if client's STDOUT is a Linux virtual console
+	isLinuxConsole bool
+	transport      *http.Transport
 }
 
 var funcMap = template.FuncMap{
@@ -172,18 +174,35 @@ func NewDockerCli(in io.ReadCloser, out, err io.Writer, keyFile string, proto, a
 	}
 
 	return &DockerCli{
-		proto:         proto,
-		addr:          addr,
-		in:            in,
-		out:           out,
-		err:           err,
-		keyFile:       keyFile,
-		inFd:          inFd,
-		outFd:         outFd,
-		isTerminalIn:  isTerminalIn,
-		isTerminalOut: isTerminalOut,
-		tlsConfig:     tlsConfig,
-		scheme:        scheme,
-		transport:     tr,
+		proto:          proto,
+		addr:           addr,
+		in:             in,
+		out:            out,
+		err:            err,
+		keyFile:        keyFile,
+		inFd:           inFd,
+		outFd:          outFd,
+		isTerminalIn:   isTerminalIn,
+		isTerminalOut:  isTerminalOut,
+		isLinuxConsole: isTerminalOut && term.IsLinuxConsole(outFd),
+		tlsConfig:      tlsConfig,
+		scheme:         scheme,
+		transport:      tr,
 	}
 }
+
+// ttyOutput returns the writer for the output of a container with a tty, out
+// being where it goes. The physical console of a server lacks many xterm
+// features containers expect, so when out is the client's STDOUT and a Linux
+// virtual console, the output is adapted to it: unsupported control strings
+// and window operations are dropped and colors are mapped to its palette.
+// Everything else, including in raw mode the cursor movements of full-screen
+// programs, is written unchanged. Without a tty the output of a container is
+// plain text and is left alone, and so is STDERR, which a container with a tty
+// doesn't have.
+func (cli *DockerCli) ttyOutput(out io.Writer) io.Writer {
+	if cli.isLinuxConsole && out == cli.out {
+		return term.NewLinuxConsoleWriter(out)
+	}
+	return out
+}
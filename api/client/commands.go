@@ -336,17 +336,28 @@ func (cli *DockerCli) CmdLogin(args ...string) error {
 	// the password or email from the config file, so prompt them
 	if username != authconfig.Username {
 		if password == "" {
-			oldState, err := term.SaveState(cli.inFd)
+			var (
+				in   io.Reader = cli.in
+				inFd           = cli.inFd
+			)
+			if !cli.isTerminalIn {
+				// stdin is redirected, read the password from the terminal
+				if tty, err := term.OpenTerminalInput(); err == nil {
+					defer tty.Close()
+					in, inFd = tty, tty.Fd()
+				}
+			}
+			oldState, err := term.SaveState(inFd)
 			if err != nil {
 				return err
 			}
 			fmt.Fprintf(cli.out, "Password: ")
-			term.DisableEcho(cli.inFd, oldState)
+			term.DisableEcho(inFd, oldState)
 
-			password = readInput(cli.in, cli.out)
+			password = readInput(in, cli.out)
 			fmt.Fprint(cli.out, "\n")
 
-			term.RestoreTerminal(cli.inFd, oldState)
+			term.RestoreTerminal(inFd, oldState)
 			if password == "" {
 				return fmt.Errorf("Error : Password Required")
 			}
@@ -766,12 +777,17 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 	}
 
 	if *openStdin || *attach {
+		stopResize := func() {}
 		if tty && cli.isTerminalOut {
-			if err := cli.monitorTtySize(cmd.Arg(0), false); err != nil {
+			if stop, err := cli.monitorTtySize(cmd.Arg(0), false); err != nil {
 				log.Errorf("Error monitoring TTY size: %s", err)
+			} else {
+				stopResize = stop
 			}
 		}
-		if attchErr := <-cErr; attchErr != nil {
+		attchErr := <-cErr
+		stopResize()
+		if attchErr != nil {
 			return attchErr
 		}
 		_, status, err := getExitCode(cli, cmd.Arg(0))
@@ -1882,10 +1898,40 @@ func (cli *DockerCli) CmdLogs(args ...string) error {
 
 	if *follow {
 		v.Set("follow", "1")
+		// Don't let an accidental Ctrl+S freeze the followed output
+		if cli.isTerminalIn {
+			if oldState, err := term.SetFlowControl(cli.inFd, false); err == nil {
+				// -f is usually ended with Ctrl+C, which skips the
+				// deferred restore
+				defer term.RestoreTerminal(cli.inFd, oldState)
+				defer term.RestoreOnExit(cli.inFd, oldState)()
+			}
+		}
 	}
 	v.Set("tail", *tail)
 
-	return cli.streamHelper("GET", "/containers/"+name+"/logs?"+v.Encode(), env.GetSubEnv("Config").GetBool("Tty"), nil, cli.out, cli.err, nil)
+	// Logs may come from an untrusted container, don't let them change the
+	// window title, fill the clipboard or type answers to queries
+	tty := env.GetSubEnv("Config").GetBool("Tty")
+	// The output of a container with a tty is meant for a terminal, keep
+	// the files it is redirected to readable
+	policy := term.RedirectPassthrough
+	if tty {
+		policy = term.RedirectStrip
+	}
+	stdout := term.NewRedirectWriter(cli.out, policy)
+	stderr := term.NewRedirectWriter(cli.err, policy)
+	if tty {
+		stdout = cli.ttyOutput(stdout)
+	}
+	if cli.isTerminalOut {
+		stdout = term.NewSanitizingWriter(stdout, term.SanitizePolicy{})
+	}
+	if f, ok := stderr.(*os.File); ok && term.IsTerminal(f.Fd()) {
+		stderr = term.NewSanitizingWriter(stderr, term.SanitizePolicy{})
+	}
+
+	return cli.streamHelper("GET", "/containers/"+name+"/logs?"+v.Encode(), tty, nil, stdout, stderr, nil)
 }
 
 func (cli *DockerCli) CmdAttach(args ...string) error {
@@ -1923,8 +1969,10 @@ func (cli *DockerCli) CmdAttach(args ...string) error {
 	}
 
 	if tty && cli.isTerminalOut {
-		if err := cli.monitorTtySize(cmd.Arg(0), false); err != nil {
+		if stop, err := cli.monitorTtySize(cmd.Arg(0), false); err != nil {
 			log.Debugf("Error monitoring TTY size: %s", err)
+		} else {
+			defer stop()
 		}
 	}
 
@@ -2349,14 +2397,19 @@ func (cli *DockerCli) CmdRun(args ...string) error {
 		return err
 	}
 
+	stopResize := func() {}
 	if (config.AttachStdin || config.AttachStdout || config.AttachStderr) && config.Tty && cli.isTerminalOut {
-		if err := cli.monitorTtySize(runResult.Get("Id"), false); err != nil {
+		if stop, err := cli.monitorTtySize(runResult.Get("Id"), false); err != nil {
 			log.Errorf("Error monitoring TTY size: %s", err)
+		} else {
+			stopResize = stop
 		}
 	}
 
 	if errCh != nil {
-		if err := <-errCh; err != nil {
+		err := <-errCh
+		stopResize()
+		if err != nil {
 			log.Debugf("Error hijack: %s", err)
 			return err
 		}
@@ -2587,13 +2640,18 @@ func (cli *DockerCli) CmdExec(args ...string) error {
 		}
 	}
 
+	stopResize := func() {}
 	if execConfig.Tty && cli.isTerminalIn {
-		if err := cli.monitorTtySize(execID, true); err != nil {
+		if stop, err := cli.monitorTtySize(execID, true); err != nil {
 			log.Errorf("Error monitoring TTY size: %s", err)
+		} else {
+			stopResize = stop
 		}
 	}
 
-	if err := <-errCh; err != nil {
+	err = <-errCh
+	stopResize()
+	if err != nil {
 		log.Debugf("Error hijack: %s", err)
 		return err
 	}
@@ -13,11 +13,8 @@ import (
 	"strings"
 	"time"
 
-	log "github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/dockerversion"
-	"github.com/docker/docker/pkg/promise"
-	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
 )
 
@@ -177,8 +174,6 @@ func (cli *DockerCli) hijack(method, path string, setRawTerminal bool, in io.Rea
 		started <- rwc
 	}
 
-	var receiveStdout chan error
-
 	var oldState *term.State
 
 	if in != nil && setRawTerminal && cli.isTerminalIn && os.Getenv("NORAW") == "" {
@@ -187,64 +182,27 @@ func (cli *DockerCli) hijack(method, path string, setRawTerminal bool, in io.Rea
 			return err
 		}
 		defer term.RestoreTerminal(cli.inFd, oldState)
+		defer term.RestoreOnExit(cli.inFd, oldState)()
+		defer term.RestoreOnPanic()
+		// the container may set the title of the console window
+		defer term.SaveTitle()()
 	}
 
-	if stdout != nil || stderr != nil {
-		receiveStdout = promise.Go(func() (err error) {
-			defer func() {
-				if in != nil {
-					if setRawTerminal && cli.isTerminalIn {
-						term.RestoreTerminal(cli.inFd, oldState)
-					}
-					// For some reason this Close call blocks on darwin..
-					// As the client exists right after, simply discard the close
-					// until we find a better solution.
-					if runtime.GOOS != "darwin" {
-						in.Close()
-					}
-				}
-			}()
-
-			// When TTY is ON, use regular copy
-			if setRawTerminal && stdout != nil {
-				_, err = io.Copy(stdout, br)
-			} else {
-				_, err = stdcopy.StdCopy(stdout, stderr, br)
-			}
-			log.Debugf("[hijack] End of stdout")
-			return err
-		})
+	if setRawTerminal {
+		stdout = cli.ttyOutput(stdout)
 	}
 
-	sendStdin := promise.Go(func() error {
+	return bridgeStreams(br, rwc, in, stdout, stderr, setRawTerminal, !cli.isTerminalIn, func() {
 		if in != nil {
-			io.Copy(rwc, in)
-			log.Debugf("[hijack] End of stdin")
-		}
-
-		if conn, ok := rwc.(interface {
-			CloseWrite() error
-		}); ok {
-			if err := conn.CloseWrite(); err != nil {
-				log.Debugf("Couldn't send EOF: %s", err)
+			if setRawTerminal && cli.isTerminalIn {
+				term.RestoreTerminal(cli.inFd, oldState)
+			}
+			// For some reason this Close call blocks on darwin..
+			// As the client exists right after, simply discard the close
+			// until we find a better solution.
+			if runtime.GOOS != "darwin" {
+				in.Close()
 			}
 		}
-		// Discard errors due to pipe interruption
-		return nil
 	})
-
-	if stdout != nil || stderr != nil {
-		if err := <-receiveStdout; err != nil {
-			log.Debugf("Error receiveStdout: %s", err)
-			return err
-		}
-	}
-
-	if !cli.isTerminalIn {
-		if err := <-sendStdin; err != nil {
-			log.Debugf("Error sendStdin: %s", err)
-			return err
-		}
-	}
-	return nil
 }
@@ -10,8 +10,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"os"
-	gosignal "os/signal"
 	"strconv"
 	"strings"
 
@@ -19,7 +17,6 @@ import (
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/engine"
-	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
 	"github.com/docker/docker/registry"
@@ -167,26 +164,20 @@ func (cli *DockerCli) streamHelper(method, path string, setRawTerminal bool, in
 		return utils.DisplayJSONMessagesStream(resp.Body, stdout, cli.outFd, cli.isTerminalOut)
 	}
 	if stdout != nil || stderr != nil {
-		// When TTY is ON, use regular copy
-		if setRawTerminal {
-			_, err = io.Copy(stdout, resp.Body)
-		} else {
-			_, err = stdcopy.StdCopy(stdout, stderr, resp.Body)
-		}
+		_, err = stdcopy.CopyOutput(stdout, stderr, resp.Body, setRawTerminal)
 		log.Debugf("[stream] End of stdout")
 		return err
 	}
 	return nil
 }
 
-func (cli *DockerCli) resizeTty(id string, isExec bool) {
-	height, width := cli.getTtySize()
-	if height == 0 && width == 0 {
-		return
+func (cli *DockerCli) resizeTty(id string, isExec bool, ws term.Winsize) error {
+	if ws.Height == 0 && ws.Width == 0 {
+		return nil
 	}
 	v := url.Values{}
-	v.Set("h", strconv.Itoa(height))
-	v.Set("w", strconv.Itoa(width))
+	v.Set("h", strconv.Itoa(int(ws.Height)))
+	v.Set("w", strconv.Itoa(int(ws.Width)))
 
 	path := ""
 	if !isExec {
@@ -197,7 +188,9 @@ func (cli *DockerCli) resizeTty(id string, isExec bool) {
 
 	if _, _, err := readBody(cli.call("POST", path+v.Encode(), nil, false)); err != nil {
 		log.Debugf("Error resize: %s", err)
+		return err
 	}
+	return nil
 }
 
 func waitForExit(cli *DockerCli, containerId string) (int, error) {
@@ -254,31 +247,14 @@ func getExecExitCode(cli *DockerCli, execId string) (bool, int, error) {
 	return result.GetBool("Running"), result.GetInt("ExitCode"), nil
 }
 
-func (cli *DockerCli) monitorTtySize(id string, isExec bool) error {
-	cli.resizeTty(id, isExec)
-
-	sigchan := make(chan os.Signal, 1)
-	gosignal.Notify(sigchan, signal.SIGWINCH)
-	go func() {
-		for _ = range sigchan {
-			cli.resizeTty(id, isExec)
-		}
-	}()
-	return nil
-}
-
-func (cli *DockerCli) getTtySize() (int, int) {
-	if !cli.isTerminalOut {
-		return 0, 0
-	}
-	ws, err := term.GetWinsize(cli.outFd)
-	if err != nil {
-		log.Debugf("Error getting size: %s", err)
-		if ws == nil {
-			return 0, 0
-		}
-	}
-	return int(ws.Height), int(ws.Width)
+// monitorTtySize keeps the tty of the container or exec process id the size of
+// the terminal of the client. The returned function stops it, once the streams
+// of the process have ended.
+func (cli *DockerCli) monitorTtySize(id string, isExec bool) (stop func(), err error) {
+	stop = term.MonitorTtySize(cli.outFd, func(ws term.Winsize) error {
+		return cli.resizeTty(id, isExec, ws)
+	})
+	return stop, nil
 }
 
 func readBody(stream io.ReadCloser, statusCode int, err error) ([]byte, int, error) {
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"io/ioutil"
 
 	log "github.com/Sirupsen/logrus"
 )
@@ -170,3 +171,24 @@ func StdCopy(dstout, dsterr io.Writer, src io.Reader) (written int64, err error)
 		nr -= frameSize + StdWriterPrefixLen
 	}
 }
+
+// CopyOutput copies the output of a container from `src` to `dstout` and
+// `dsterr`. With a tty, the container has a single output stream, sent as is
+// and copied to `dstout`; otherwise `src` is demultiplexed with StdCopy.
+// A nil destination discards what would be written to it.
+//
+// Writers adapting the output to the terminal, if any, belong on `dstout` and
+// `dsterr`, where they get the output of the container without the headers
+// of the multiplexed stream.
+func CopyOutput(dstout, dsterr io.Writer, src io.Reader, tty bool) (written int64, err error) {
+	if dstout == nil {
+		dstout = ioutil.Discard
+	}
+	if dsterr == nil {
+		dsterr = ioutil.Discard
+	}
+	if tty {
+		return io.Copy(dstout, src)
+	}
+	return StdCopy(dstout, dsterr, src)
+}
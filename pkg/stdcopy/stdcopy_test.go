@@ -18,3 +18,29 @@ func BenchmarkWrite(b *testing.B) {
 		}
 	}
 }
+
+func TestCopyOutput(t *testing.T) {
+	var muxed bytes.Buffer
+	NewStdWriter(&muxed, Stdout).Write([]byte("out"))
+	NewStdWriter(&muxed, Stderr).Write([]byte("err"))
+
+	var stdout, stderr bytes.Buffer
+	if _, err := CopyOutput(&stdout, &stderr, bytes.NewReader(muxed.Bytes()), false); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "out" || stderr.String() != "err" {
+		t.Fatalf("Unexpected output: stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	if _, err := CopyOutput(&stdout, nil, bytes.NewReader([]byte("raw\x01")), true); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "raw\x01" {
+		t.Fatalf("Unexpected output with a tty: %q", stdout.String())
+	}
+
+	if _, err := CopyOutput(nil, nil, bytes.NewReader(muxed.Bytes()), false); err != nil {
+		t.Fatal(err)
+	}
+}
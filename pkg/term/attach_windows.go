@@ -0,0 +1,120 @@
+// +build windows
+
+package term
+
+import "os"
+
+// ATTACH_PARENT_PROCESS makes AttachConsole use the console of the parent
+// process.
+const ATTACH_PARENT_PROCESS = ^uint32(0)
+
+var (
+	allocConsoleProc  = kernel32DLL.NewProc("AllocConsole")
+	attachConsoleProc = kernel32DLL.NewProc("AttachConsole")
+	freeConsoleProc   = kernel32DLL.NewProc("FreeConsole")
+)
+
+// AllocConsole creates a new console for the calling process, which must not
+// be attached to one already.
+func AllocConsole() error {
+	if _, err := callProc(allocConsoleProc); err != nil {
+		return err
+	}
+	RefreshStdHandles()
+	return nil
+}
+
+// AttachConsole attaches the calling process to the console of process pid,
+// or of its parent with ATTACH_PARENT_PROCESS.
+func AttachConsole(pid uint32) error {
+	if _, err := callProc(attachConsoleProc, uintptr(pid)); err != nil {
+		return err
+	}
+	RefreshStdHandles()
+	return nil
+}
+
+// FreeConsole detaches the calling process from its console.
+func FreeConsole() error {
+	if _, err := callProc(freeConsoleProc); err != nil {
+		return err
+	}
+	RefreshStdHandles()
+	return nil
+}
+
+// AttachOrAllocConsole gives a process started without a console, such as a
+// GUI-subsystem binary or a service, one to run interactive sessions in: the
+// console of its parent if it has one, or a new one otherwise. The returned
+// files are opened on the console input and output buffers.
+func AttachOrAllocConsole() (stdin, stdout, stderr *os.File, err error) {
+	if err := AttachConsole(ATTACH_PARENT_PROCESS); err != nil {
+		if err := AllocConsole(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return OpenConsole()
+}
+
+// OpenConsole opens the input and output buffers of the console the process
+// is attached to, regardless of where its std handles point.
+func OpenConsole() (stdin, stdout, stderr *os.File, err error) {
+	stdin, err = openConsoleInput()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stdout, err = openConsoleOutput()
+	if err != nil {
+		stdin.Close()
+		return nil, nil, nil, err
+	}
+	stderr, err = openConsoleOutput()
+	if err != nil {
+		stdin.Close()
+		stdout.Close()
+		return nil, nil, nil, err
+	}
+	return stdin, stdout, stderr, nil
+}
+
+// openConsoleInput opens the input buffer of the console the process is
+// attached to.
+func openConsoleInput() (*os.File, error) {
+	return os.OpenFile("CONIN$", os.O_RDWR, 0)
+}
+
+// openConsoleOutput opens the active screen buffer of the console the process
+// is attached to.
+func openConsoleOutput() (*os.File, error) {
+	return os.OpenFile("CONOUT$", os.O_RDWR, 0)
+}
+
+// PromptStreams returns the streams to use for interactive prompts, such as
+// confirmations or password input: in and out themselves when they are
+// consoles, otherwise the console input and output buffers opened directly, as
+// long as the process has a console. Regular output keeps going to out. The
+// returned function closes what was opened and must be called when done.
+func PromptStreams(in, out *os.File) (promptIn, promptOut *os.File, closeFn func(), err error) {
+	var opened []*os.File
+	closeFn = func() {
+		for _, f := range opened {
+			f.Close()
+		}
+	}
+
+	promptIn, promptOut = in, out
+	if !IsTerminal(in.Fd()) {
+		if promptIn, err = openConsoleInput(); err != nil {
+			return nil, nil, nil, err
+		}
+		opened = append(opened, promptIn)
+	}
+	if !IsTerminal(out.Fd()) {
+		if promptOut, err = openConsoleOutput(); err != nil {
+			closeFn()
+			return nil, nil, nil, err
+		}
+		opened = append(opened, promptOut)
+	}
+	return promptIn, promptOut, closeFn, nil
+}
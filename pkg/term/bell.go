@@ -0,0 +1,13 @@
+// +build !windows
+
+package term
+
+import "syscall"
+
+// Bell rings the bell of the terminal connected to the given file descriptor,
+// e.g. when a long operation finishes. Whether that makes a sound or flashes
+// the screen is up to the terminal.
+func Bell(fd uintptr) error {
+	_, err := syscall.Write(int(fd), []byte{'\a'})
+	return err
+}
@@ -0,0 +1,32 @@
+// +build windows
+
+package term
+
+import "syscall"
+
+// MB_OK plays the default sound of the sound scheme.
+const MB_OK = 0x00000000
+
+var (
+	user32DLL       = syscall.NewLazyDLL("user32.dll")
+	messageBeepProc = user32DLL.NewProc("MessageBeep")
+)
+
+// Bell rings the bell of the console connected to the given handle, e.g. when
+// a long operation finishes. It plays the default sound of the user's sound
+// scheme, which honors the settings to mute it or to show a visual
+// notification instead (SoundSentry). Cygwin ptys get a BEL character, for
+// the terminal emulator to handle.
+func Bell(fd uintptr) error {
+	if IsCygwinTerminal(fd) {
+		_, err := syscall.Write(syscall.Handle(fd), []byte{'\a'})
+		return err
+	}
+	if !IsTerminal(fd) {
+		return syscall.EINVAL
+	}
+	if _, err := callProc(messageBeepProc, MB_OK); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package term
+
+import (
+	"os"
+	"strings"
+)
+
+// Capabilities describes what the local terminal can display.
+type Capabilities struct {
+	// Terminal is set when the output is a terminal at all.
+	Terminal bool
+	// VT is set when the terminal interprets VT100/xterm escape sequences.
+	VT bool
+	// Colors is the number of colors the terminal can display: 8, 16, 256,
+	// or 1<<24 for 24-bit color.
+	Colors int
+	// LinuxConsole is set for the Linux virtual console.
+	LinuxConsole bool
+}
+
+// RecommendedTERM returns the TERM value to set in a container attached to a
+// terminal with the given capabilities, so that programs in the container only
+// use the features it supports.
+func RecommendedTERM(caps Capabilities) string {
+	switch {
+	case !caps.Terminal || !caps.VT:
+		return "dumb"
+	case caps.LinuxConsole:
+		return "linux"
+	case caps.Colors >= 256:
+		return "xterm-256color"
+	}
+	return "xterm"
+}
+
+// TerminalEnv returns the environment variables, as KEY=value, describing a
+// terminal with the given capabilities to programs in a container: TERM, as
+// returned by RecommendedTERM, and COLORTERM for 24-bit color.
+func TerminalEnv(caps Capabilities) []string {
+	env := []string{"TERM=" + RecommendedTERM(caps)}
+	if caps.Terminal && caps.VT && caps.Colors >= 1<<24 {
+		env = append(env, "COLORTERM=truecolor")
+	}
+	return env
+}
+
+// AddTerminalEnv returns env with the variables of TerminalEnv added, except
+// those env sets already, e.g. through docker run -e, which take precedence.
+func AddTerminalEnv(env []string, caps Capabilities) []string {
+	for _, v := range TerminalEnv(caps) {
+		key := v[:strings.Index(v, "=")+1]
+		set := false
+		for _, e := range env {
+			if strings.HasPrefix(e, key) || e == key[:len(key)-1] {
+				set = true
+				break
+			}
+		}
+		if !set {
+			env = append(env, v)
+		}
+	}
+	return env
+}
+
+// colorsFromEnv returns the number of colors of the terminal described by the
+// TERM and COLORTERM environment variables.
+func colorsFromEnv() int {
+	if colorterm := os.Getenv("COLORTERM"); colorterm == "truecolor" || colorterm == "24bit" {
+		return 1 << 24
+	}
+	switch t := os.Getenv("TERM"); {
+	case strings.HasSuffix(t, "-direct"):
+		return 1 << 24
+	case strings.Contains(t, "256color"):
+		return 256
+	case t == "linux":
+		return 8
+	}
+	return 16
+}
@@ -0,0 +1,44 @@
+package term
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecommendedTERM(t *testing.T) {
+	for _, c := range []struct {
+		caps     Capabilities
+		expected string
+	}{
+		{Capabilities{}, "dumb"},
+		{Capabilities{Terminal: true, Colors: 16}, "dumb"},
+		{Capabilities{Terminal: true, VT: true, Colors: 8, LinuxConsole: true}, "linux"},
+		{Capabilities{Terminal: true, VT: true, Colors: 16}, "xterm"},
+		{Capabilities{Terminal: true, VT: true, Colors: 256}, "xterm-256color"},
+		{Capabilities{Terminal: true, VT: true, Colors: 1 << 24}, "xterm-256color"},
+	} {
+		if actual := RecommendedTERM(c.caps); actual != c.expected {
+			t.Errorf("RecommendedTERM(%+v) = %q, expected %q", c.caps, actual, c.expected)
+		}
+	}
+}
+
+func TestTerminalEnv(t *testing.T) {
+	for _, c := range []struct {
+		caps     Capabilities
+		env      []string
+		expected []string
+	}{
+		{Capabilities{}, nil, []string{"TERM=dumb"}},
+		{Capabilities{Terminal: true, VT: true, Colors: 256}, []string{"A=b"}, []string{"A=b", "TERM=xterm-256color"}},
+		{Capabilities{Terminal: true, VT: true, Colors: 1 << 24}, nil, []string{"TERM=xterm-256color", "COLORTERM=truecolor"}},
+		{Capabilities{Terminal: true, VT: true, Colors: 1 << 24}, []string{"TERM=screen"}, []string{"TERM=screen", "COLORTERM=truecolor"}},
+		// set from the environment of the client, as with docker run -e TERM
+		{Capabilities{Terminal: true, VT: true, Colors: 16}, []string{"TERM"}, []string{"TERM"}},
+		{Capabilities{Terminal: true, VT: true, Colors: 16}, []string{"TERMINAL=x"}, []string{"TERMINAL=x", "TERM=xterm"}},
+	} {
+		if actual := AddTerminalEnv(c.env, c.caps); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("AddTerminalEnv(%q, %+v) = %q, expected %q", c.env, c.caps, actual, c.expected)
+		}
+	}
+}
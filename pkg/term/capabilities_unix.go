@@ -0,0 +1,20 @@
+// +build !windows
+
+package term
+
+import "os"
+
+// DetectCapabilities returns the capabilities of the terminal connected to
+// the given file descriptor, as described by TERM and COLORTERM.
+func DetectCapabilities(fd uintptr) Capabilities {
+	if !IsTerminal(fd) {
+		return Capabilities{}
+	}
+	term := os.Getenv("TERM")
+	return Capabilities{
+		Terminal:     true,
+		VT:           term != "" && term != "dumb",
+		Colors:       colorsFromEnv(),
+		LinuxConsole: IsLinuxConsole(fd),
+	}
+}
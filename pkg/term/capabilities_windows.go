@@ -0,0 +1,24 @@
+// +build windows
+
+package term
+
+// DetectCapabilities returns the capabilities of the console connected to the
+// given handle. Consoles interpreting VT sequences support 24-bit color, except
+// for Cygwin ptys, which are described by TERM and COLORTERM.
+func DetectCapabilities(fd uintptr) Capabilities {
+	host := DetectConsoleHost(fd)
+	caps := Capabilities{
+		Terminal: host.Host != HostUnknown,
+		VT:       host.VT,
+		Colors:   16,
+	}
+	switch host.Host {
+	case HostCygwin:
+		caps.Colors = colorsFromEnv()
+	case HostConhost, HostWindowsTerminal:
+		caps.Colors = 1 << 24
+	case HostThirdParty:
+		caps.Colors = 256
+	}
+	return caps
+}
@@ -0,0 +1,138 @@
+package term
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CastRecorder records the output of a terminal session in the asciinema v2
+// format (https://docs.asciinema.org/manual/asciicast/v2/), so that it can be
+// played back or shared, e.g. to show what happened in a docker exec session.
+type CastRecorder struct {
+	w io.Writer
+
+	mu    sync.Mutex
+	start time.Time
+	now   func() time.Time
+	// partial holds the bytes of a UTF-8 character split across writes,
+	// which can only be recorded as a whole.
+	partial []byte
+	err     error
+}
+
+// castHeader is the first line of a cast file.
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// NewCastRecorder writes the header of a recording of a terminal of size ws
+// to w and returns a CastRecorder writing the events of the session after it.
+func NewCastRecorder(w io.Writer, ws *Winsize) (*CastRecorder, error) {
+	r := &CastRecorder{w: w, now: time.Now}
+	return r, r.writeHeader(ws)
+}
+
+func (r *CastRecorder) writeHeader(ws *Winsize) error {
+	r.start = r.now()
+	header := castHeader{
+		Version:   2,
+		Width:     ws.Width,
+		Height:    ws.Height,
+		Timestamp: r.start.Unix(),
+	}
+	if term := os.Getenv("TERM"); term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	return r.writeLine(header)
+}
+
+// Writer returns a writer that writes to out and records what it writes as
+// output of the session.
+func (r *CastRecorder) Writer(out io.Writer) io.Writer {
+	return &castWriter{out: out, r: r}
+}
+
+type castWriter struct {
+	out io.Writer
+	r   *CastRecorder
+}
+
+func (c *castWriter) Write(p []byte) (int, error) {
+	n, err := c.out.Write(p)
+	if n > 0 {
+		c.r.output(p[:n])
+	}
+	return n, err
+}
+
+// Resize records a change of the size of the terminal.
+func (r *CastRecorder) Resize(ws *Winsize) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.event("r", fmt.Sprintf("%dx%d", ws.Width, ws.Height))
+}
+
+// Err returns the first error met writing the recording, which stops it. The
+// output of the session keeps going through the writers returned by Writer.
+func (r *CastRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *CastRecorder) output(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := append(r.partial, p...)
+	n := len(data) - incompleteUTF8Suffix(data)
+	r.partial = append([]byte(nil), data[n:]...)
+	if n > 0 {
+		r.event("o", string(data[:n]))
+	}
+}
+
+func (r *CastRecorder) event(kind, data string) error {
+	elapsed := r.now().Sub(r.start).Seconds()
+	return r.writeLine([]interface{}{elapsed, kind, data})
+}
+
+func (r *CastRecorder) writeLine(v interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return err
+	}
+	if _, err := r.w.Write(append(b, '\n')); err != nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// incompleteUTF8Suffix returns the length of the start of a UTF-8 character at
+// the end of p, 0 if p ends with a complete character.
+func incompleteUTF8Suffix(p []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(p); i++ {
+		b := p[len(p)-i]
+		if !utf8.RuneStart(b) {
+			continue
+		}
+		if utf8.FullRune(p[len(p)-i:]) {
+			return 0
+		}
+		return i
+	}
+	return 0
+}
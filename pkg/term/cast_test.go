@@ -0,0 +1,62 @@
+package term
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCastRecorder(t *testing.T) {
+	var cast, out bytes.Buffer
+	clock := time.Unix(1400000000, 0)
+	r := &CastRecorder{w: &cast, now: func() time.Time { return clock }}
+	if err := r.writeHeader(&Winsize{Width: 80, Height: 24}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := r.Writer(&out)
+	clock = clock.Add(500 * time.Millisecond)
+	w.Write([]byte("hello \xc3"))
+	clock = clock.Add(time.Second)
+	w.Write([]byte("\xa9\x1b[0m\r\n"))
+	r.Resize(&Winsize{Width: 100, Height: 30})
+
+	if out.String() != "hello é\x1b[0m\r\n" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(cast.String(), "\n"), "\n")
+	expected := []string{
+		`{"version":2,"width":80,"height":24,"timestamp":1400000000`,
+		`[0.5,"o","hello "]`,
+		`[1.5,"o","é\u001b[0m\r\n"]`,
+		`[1.5,"r","100x30"]`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), lines)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, expected[i]) {
+			t.Errorf("line %d: expected %s, got %s", i, expected[i], line)
+		}
+	}
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIncompleteUTF8Suffix(t *testing.T) {
+	for s, n := range map[string]int{
+		"":              0,
+		"abc":           0,
+		"é":             0,
+		"a\xc3":         1,
+		"a\xe2\x82":     2,
+		"a\xf0\x9f\x98": 3,
+		"\xff":          0,
+	} {
+		if got := incompleteUTF8Suffix([]byte(s)); got != n {
+			t.Errorf("%q: expected %d, got %d", s, n, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package term
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// maxCoalescedBytes bounds the output a CoalescingWriter holds back: a write
+// making it larger is flushed right away, which slows down the writer to the
+// pace of the terminal.
+const maxCoalescedBytes = 1 << 20
+
+// clearScreen is the sequence full-screen programs start a redraw with.
+var clearScreen = []byte("\x1b[2J")
+
+// CoalescingWriter keeps a terminal responsive when a container writes faster
+// than it can display: output is written out at most once per window, and
+// when output held back contains several screen redraws, each starting with
+// ESC[2J, only the last one is written. Skipped redraws are dropped whole,
+// including the attributes or modes they set, which is fine for programs
+// redrawing everything after clearing the screen, such as top or watch, but
+// not in general, hence the writer is opt-in.
+type CoalescingWriter struct {
+	w      io.Writer
+	window time.Duration
+
+	mu      sync.Mutex
+	buf     []byte
+	pending bool
+	err     error
+}
+
+// NewCoalescingWriter returns a CoalescingWriter writing to w at most once per
+// window. Close must be called to write out the output held back at the end.
+func NewCoalescingWriter(w io.Writer, window time.Duration) *CoalescingWriter {
+	return &CoalescingWriter{w: w, window: window}
+}
+
+func (c *CoalescingWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+	c.buf = append(c.buf, p...)
+	if i := bytes.LastIndex(c.buf, clearScreen); i > 0 {
+		if first := bytes.Index(c.buf, clearScreen); first < i {
+			// keep what came before the first redraw, e.g. a switch
+			// to the alternate screen, and the last redraw
+			c.buf = append(c.buf[:first], c.buf[i:]...)
+		}
+	}
+
+	if len(c.buf) >= maxCoalescedBytes {
+		if err := c.flush(); err != nil {
+			return 0, err
+		}
+	} else if !c.pending {
+		c.pending = true
+		time.AfterFunc(c.window, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.pending = false
+			c.flush()
+		})
+	}
+	return len(p), nil
+}
+
+// Flush writes out the output held back.
+func (c *CoalescingWriter) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.flush()
+}
+
+// Close writes out the output held back. The writer must not be used
+// afterwards.
+func (c *CoalescingWriter) Close() error {
+	return c.Flush()
+}
+
+func (c *CoalescingWriter) flush() error {
+	if c.err != nil || len(c.buf) == 0 {
+		return c.err
+	}
+	_, c.err = c.w.Write(c.buf)
+	c.buf = c.buf[:0]
+	return c.err
+}
@@ -0,0 +1,57 @@
+package term
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	sync.Mutex
+	bytes.Buffer
+	writes int
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	b.writes++
+	return b.Buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.Buffer.String()
+}
+
+func TestCoalescingWriter(t *testing.T) {
+	var buf lockedBuffer
+	w := NewCoalescingWriter(&buf, 50*time.Millisecond)
+
+	for _, s := range []string{"\x1b[?1049h", "\x1b[2Jframe 1", "\x1b[2Jframe 2", "\x1b[2Jframe 3", " more"} {
+		if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if s := buf.String(); s != "" {
+		t.Fatalf("%q written before the end of the window", s)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if s, expected := buf.String(), "\x1b[?1049h\x1b[2Jframe 3 more"; s != expected {
+		t.Fatalf("%q written after the window, expected %q", s, expected)
+	}
+	if buf.writes != 1 {
+		t.Fatalf("%d writes, expected 1", buf.writes)
+	}
+
+	w.Write([]byte("last"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "\x1b[?1049h\x1b[2Jframe 3 morelast" {
+		t.Fatalf("Close did not write out the output held back: %q", s)
+	}
+}
@@ -0,0 +1,59 @@
+// +build windows
+
+package term
+
+// CP_UTF8 is the code page identifier of UTF-8
+const CP_UTF8 = 65001
+
+var (
+	getConsoleCPProc       = kernel32DLL.NewProc("GetConsoleCP")
+	getConsoleOutputCPProc = kernel32DLL.NewProc("GetConsoleOutputCP")
+	setConsoleCPProc       = kernel32DLL.NewProc("SetConsoleCP")
+	setConsoleOutputCPProc = kernel32DLL.NewProc("SetConsoleOutputCP")
+)
+
+// CodePages holds the input and output code pages of the console the process
+// is attached to.
+type CodePages struct {
+	Input  uint32
+	Output uint32
+}
+
+// GetCodePages returns the code pages currently used by the console.
+func GetCodePages() (*CodePages, error) {
+	in, err := callProc(getConsoleCPProc)
+	if err != nil {
+		return nil, err
+	}
+	out, err := callProc(getConsoleOutputCPProc)
+	if err != nil {
+		return nil, err
+	}
+	return &CodePages{Input: uint32(in), Output: uint32(out)}, nil
+}
+
+// SetCodePages sets the code pages used by the console.
+func SetCodePages(cp *CodePages) error {
+	if _, err := callProc(setConsoleCPProc, uintptr(cp.Input)); err != nil {
+		return err
+	}
+	if _, err := callProc(setConsoleOutputCPProc, uintptr(cp.Output)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetUTF8CodePages switches the console to UTF-8 for both input and output,
+// so that container output displays correctly on legacy consoles, and returns
+// the previous code pages to be restored with SetCodePages.
+func SetUTF8CodePages() (*CodePages, error) {
+	old, err := GetCodePages()
+	if err != nil {
+		return nil, err
+	}
+	if err := SetCodePages(&CodePages{Input: CP_UTF8, Output: CP_UTF8}); err != nil {
+		SetCodePages(old)
+		return nil, err
+	}
+	return old, nil
+}
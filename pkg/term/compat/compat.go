@@ -0,0 +1,76 @@
+// Package compat exposes pkg/term with the signatures of golang.org/x/term,
+// for code moving between the two packages or using both, which can then
+// call either with the same arguments. File descriptors are ints, as in
+// x/term, and states are those of pkg/term.
+package compat
+
+import (
+	"io"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// IsTerminal returns true if fd is a terminal.
+func IsTerminal(fd int) bool {
+	return term.IsTerminal(uintptr(fd))
+}
+
+// MakeRaw puts the terminal fd into raw mode and returns its previous state.
+func MakeRaw(fd int) (*term.State, error) {
+	return term.MakeRaw(uintptr(fd))
+}
+
+// Restore restores the terminal fd to a previous state.
+func Restore(fd int, state *term.State) error {
+	return term.RestoreTerminal(uintptr(fd), state)
+}
+
+// GetSize returns the size of the terminal fd, in columns and rows.
+func GetSize(fd int) (width, height int, err error) {
+	ws, err := term.GetWinsize(uintptr(fd))
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Width), int(ws.Height), nil
+}
+
+// ReadPassword reads a line from the terminal fd with echo turned off and
+// returns it without the line ending.
+func ReadPassword(fd int) ([]byte, error) {
+	restore, err := disableEcho(uintptr(fd))
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	return readLine(fdReader(fd))
+}
+
+// readLine reads from r up to a line feed or EOF, a byte at a time so as not
+// to consume what follows, and returns what it read without the line ending.
+func readLine(r io.Reader) ([]byte, error) {
+	var (
+		line []byte
+		buf  [1]byte
+	)
+	for {
+		n, err := r.Read(buf[:])
+		if n > 0 {
+			switch buf[0] {
+			case '\n':
+				return line, nil
+			case '\r':
+				// part of CR LF on Windows
+			default:
+				line = append(line, buf[0])
+			}
+			continue
+		}
+		if err == io.EOF && len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			return line, err
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// +build !windows
+
+package compat
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/pkg/term"
+	"github.com/docker/docker/pkg/term/pty"
+)
+
+func TestCompat(t *testing.T) {
+	p, err := pty.Open(&term.Winsize{Height: 24, Width: 80})
+	if err != nil {
+		t.Skipf("Cannot allocate a pty: %s", err)
+	}
+	defer p.Close()
+	fd := int(p.Slave.Fd())
+
+	if !IsTerminal(fd) {
+		t.Fatal("the slave is not a terminal")
+	}
+	if w, h, err := GetSize(fd); err != nil || w != 80 || h != 24 {
+		t.Fatalf("GetSize() = %d, %d, %v, expected 80, 24", w, h, err)
+	}
+	state, err := MakeRaw(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(fd, state); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Master.Write([]byte("s3cret\n"))
+	password, err := ReadPassword(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(password) != "s3cret" {
+		t.Fatalf("ReadPassword() = %q", password)
+	}
+}
+
+// chunkReader returns its chunks one Read at a time, then err.
+type chunkReader struct {
+	chunks []string
+	err    error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.chunks[0])
+	if r.chunks[0] = r.chunks[0][n:]; r.chunks[0] == "" {
+		r.chunks = r.chunks[1:]
+	}
+	return n, nil
+}
+
+func TestReadLine(t *testing.T) {
+	failure := errors.New("failure")
+	for _, test := range []struct {
+		r        io.Reader
+		expected string
+		err      error
+	}{
+		{bytes.NewReader([]byte("pass\nnext")), "pass", nil},
+		{bytes.NewReader([]byte("pass\r\n")), "pass", nil},
+		{bytes.NewReader([]byte("pass")), "pass", nil},
+		{bytes.NewReader(nil), "", io.EOF},
+		{&chunkReader{[]string{"pa", "ss"}, failure}, "pass", failure},
+	} {
+		line, err := readLine(test.r)
+		if string(line) != test.expected || err != test.err {
+			t.Errorf("readLine() = %q, %v, expected %q, %v", line, err, test.expected, test.err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// +build !windows
+
+package compat
+
+import (
+	"io"
+	"syscall"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// fdReader reads from a file descriptor without wrapping it in an os.File,
+// which would close it when garbage collected.
+type fdReader int
+
+func (fd fdReader) Read(p []byte) (int, error) {
+	n, err := syscall.Read(int(fd), p)
+	if n < 0 {
+		n = 0
+	}
+	if n == 0 && err == nil && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, err
+}
+
+// disableEcho clears ECHO on the terminal fd, leaving the rest of its settings
+// and signal handling alone, and returns a function restoring it.
+func disableEcho(fd uintptr) (func() error, error) {
+	old, err := term.Tcgetattr(fd)
+	if err != nil {
+		return nil, err
+	}
+	termios := *old
+	termios.Lflag &^= syscall.ECHO
+	if err := term.Tcsetattr(fd, &termios); err != nil {
+		return nil, err
+	}
+	return func() error {
+		return term.Tcsetattr(fd, old)
+	}, nil
+}
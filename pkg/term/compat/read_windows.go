@@ -0,0 +1,34 @@
+// +build windows
+
+package compat
+
+import (
+	"io"
+	"syscall"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// fdReader reads from a handle without wrapping it in an os.File, which would
+// close it when garbage collected.
+type fdReader int
+
+func (fd fdReader) Read(p []byte) (int, error) {
+	n, err := syscall.Read(syscall.Handle(fd), p)
+	if n == 0 && err == nil && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, err
+}
+
+// disableEcho turns off the echo of the console input fd, keeping line input
+// and Ctrl+C processing, and returns a function restoring its previous mode.
+func disableEcho(fd uintptr) (func() error, error) {
+	state, err := term.SetConsoleModeOptions(fd, term.WithEcho(false), term.WithLineInput(true), term.WithProcessedInput(true))
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		return term.RestoreTerminal(fd, state)
+	}, nil
+}
@@ -0,0 +1,106 @@
+// +build windows
+
+package term
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	createPseudoConsoleProc = kernel32DLL.NewProc("CreatePseudoConsole")
+	resizePseudoConsoleProc = kernel32DLL.NewProc("ResizePseudoConsole")
+	closePseudoConsoleProc  = kernel32DLL.NewProc("ClosePseudoConsole")
+)
+
+var ErrPseudoConsoleUnsupported = errors.New("Pseudo consoles are not supported on this version of Windows")
+
+// PseudoConsole is a ConPTY pseudo-terminal (Windows 10 1809 and later).
+// Reads return the VT output produced by the attached process and writes are
+// delivered to it as console input.
+// see https://docs.microsoft.com/en-us/windows/console/creating-a-pseudoconsole-session
+type PseudoConsole struct {
+	handle syscall.Handle
+	input  *os.File // write end of the pseudo console input pipe
+	output *os.File // read end of the pseudo console output pipe
+}
+
+// IsPseudoConsoleSupported returns true if the running system provides the
+// ConPTY API.
+func IsPseudoConsoleSupported() bool {
+	return findProc(createPseudoConsoleProc) == nil
+}
+
+// NewPseudoConsole creates a pseudo console of the given size along with the
+// pipes used to talk to it.
+func NewPseudoConsole(ws *Winsize) (*PseudoConsole, error) {
+	if !IsPseudoConsoleSupported() {
+		return nil, ErrPseudoConsoleUnsupported
+	}
+
+	var inRead, inWrite, outRead, outWrite syscall.Handle
+	if err := syscall.CreatePipe(&inRead, &inWrite, nil, 0); err != nil {
+		return nil, err
+	}
+	if err := syscall.CreatePipe(&outRead, &outWrite, nil, 0); err != nil {
+		syscall.CloseHandle(inRead)
+		syscall.CloseHandle(inWrite)
+		return nil, err
+	}
+
+	var handle syscall.Handle
+	size := COORD{X: toShort(ws.Width), Y: toShort(ws.Height)}
+	err := callHRESULTProc(createPseudoConsoleProc, coordToUintptr(size), uintptr(inRead), uintptr(outWrite), 0, uintptr(unsafe.Pointer(&handle)))
+
+	// The pseudo console holds its own duplicates of its ends of the pipes.
+	syscall.CloseHandle(inRead)
+	syscall.CloseHandle(outWrite)
+
+	if err != nil {
+		syscall.CloseHandle(inWrite)
+		syscall.CloseHandle(outRead)
+		return nil, err
+	}
+
+	return &PseudoConsole{
+		handle: handle,
+		input:  os.NewFile(uintptr(inWrite), "conpty-input"),
+		output: os.NewFile(uintptr(outRead), "conpty-output"),
+	}, nil
+}
+
+// Handle returns the HPCON of the pseudo console, to be passed to
+// UpdateProcThreadAttribute when starting a process attached to it.
+func (p *PseudoConsole) Handle() uintptr {
+	return uintptr(p.handle)
+}
+
+// Read reads the output of the processes attached to the pseudo console.
+func (p *PseudoConsole) Read(b []byte) (int, error) {
+	return p.output.Read(b)
+}
+
+// Write sends input to the processes attached to the pseudo console.
+func (p *PseudoConsole) Write(b []byte) (int, error) {
+	return p.input.Write(b)
+}
+
+// Resize changes the size of the pseudo console.
+func (p *PseudoConsole) Resize(ws *Winsize) error {
+	size := COORD{X: toShort(ws.Width), Y: toShort(ws.Height)}
+	return callHRESULTProc(resizePseudoConsoleProc, uintptr(p.handle), coordToUintptr(size))
+}
+
+// Close closes the pseudo console, terminating the attached processes, and
+// its pipes.
+func (p *PseudoConsole) Close() error {
+	// ClosePseudoConsole returns nothing, it can only be missing
+	err := callVoidProc(closePseudoConsoleProc, uintptr(p.handle))
+	p.input.Close()
+	if outErr := p.output.Close(); err == nil {
+		err = outErr
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package term
+
+// types for calling GetConsoleScreenBufferInfo, defined on all platforms so
+// that the logic using them can be tested anywhere
+// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms682093(v=vs.85).aspx
+type (
+	SHORT int16
+
+	SMALL_RECT struct {
+		Left   SHORT
+		Top    SHORT
+		Right  SHORT
+		Bottom SHORT
+	}
+
+	COORD struct {
+		X SHORT
+		Y SHORT
+	}
+
+	WORD uint16
+
+	CONSOLE_SCREEN_BUFFER_INFO struct {
+		dwSize              COORD
+		dwCursorPosition    COORD
+		wAttributes         WORD
+		srWindow            SMALL_RECT
+		dwMaximumWindowSize COORD
+	}
+)
@@ -25,63 +25,166 @@ const (
 var kernel32DLL = syscall.NewLazyDLL("kernel32.dll")
 
 var (
-	setConsoleModeProc             = kernel32DLL.NewProc("SetConsoleMode")
-	getConsoleScreenBufferInfoProc = kernel32DLL.NewProc("GetConsoleScreenBufferInfo")
+	setConsoleModeProc               = kernel32DLL.NewProc("SetConsoleMode")
+	getConsoleScreenBufferInfoProc   = kernel32DLL.NewProc("GetConsoleScreenBufferInfo")
+	getFileInformationByHandleExProc = kernel32DLL.NewProc("GetFileInformationByHandleEx")
+	getCurrentConsoleFontExProc      = kernel32DLL.NewProc("GetCurrentConsoleFontEx")
+	setConsoleScreenBufferSizeProc   = kernel32DLL.NewProc("SetConsoleScreenBufferSize")
+	setConsoleWindowInfoProc         = kernel32DLL.NewProc("SetConsoleWindowInfo")
+	flushConsoleInputBufferProc      = kernel32DLL.NewProc("FlushConsoleInputBuffer")
 )
 
 func GetConsoleMode(fileDesc uintptr) (uint32, error) {
 	var mode uint32
-	err := syscall.GetConsoleMode(syscall.Handle(fileDesc), &mode)
+	err := withHandle(fileDesc, func(fd uintptr) error {
+		return syscall.GetConsoleMode(syscall.Handle(fd), &mode)
+	})
 	return mode, err
 }
 
 func SetConsoleMode(fileDesc uintptr, mode uint32) error {
-	r, _, err := setConsoleModeProc.Call(fileDesc, uintptr(mode), 0)
-	if r == 0 {
-		if err != nil {
-			return err
-		}
-		return syscall.EINVAL
-	}
-	return nil
+	return withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(setConsoleModeProc, fd, uintptr(mode), 0)
+		return err
+	})
 }
 
-// types for calling GetConsoleScreenBufferInfo
-// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms682093(v=vs.85).aspx
-type (
-	SHORT int16
+// coordToUintptr packs a COORD into the single DWORD argument expected by the
+// APIs that take it by value: X in the low word, Y in the high word.
+func coordToUintptr(c COORD) uintptr {
+	return uintptr(uint16(c.X)) | uintptr(uint16(c.Y))<<16
+}
 
-	SMALL_RECT struct {
-		Left   SHORT
-		Top    SHORT
-		Right  SHORT
-		Bottom SHORT
+func GetConsoleScreenBufferInfo(fileDesc uintptr) (*CONSOLE_SCREEN_BUFFER_INFO, error) {
+	var info CONSOLE_SCREEN_BUFFER_INFO
+	err := withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(getConsoleScreenBufferInfoProc, fd, uintptr(unsafe.Pointer(&info)), 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &info, nil
+}
 
-	COORD struct {
-		X SHORT
-		Y SHORT
-	}
+func SetConsoleScreenBufferSize(fileDesc uintptr, size COORD) error {
+	return withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(setConsoleScreenBufferSizeProc, fd, coordToUintptr(size))
+		return err
+	})
+}
+
+// SetConsoleWindowInfo sets the position of the console window within its
+// screen buffer, using absolute buffer coordinates.
+func SetConsoleWindowInfo(fileDesc uintptr, window SMALL_RECT) error {
+	return withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(setConsoleWindowInfoProc, fd, 1, uintptr(unsafe.Pointer(&window)))
+		return err
+	})
+}
+
+// kernel32Console is the consoleAPI of the actual console.
+type kernel32Console struct{}
 
-	WORD uint16
+func (kernel32Console) GetConsoleScreenBufferInfo(fd uintptr) (*CONSOLE_SCREEN_BUFFER_INFO, error) {
+	return GetConsoleScreenBufferInfo(fd)
+}
+
+func (kernel32Console) SetConsoleScreenBufferSize(fd uintptr, size COORD) error {
+	return SetConsoleScreenBufferSize(fd, size)
+}
+
+func (kernel32Console) SetConsoleWindowInfo(fd uintptr, window SMALL_RECT) error {
+	return SetConsoleWindowInfo(fd, window)
+}
+
+func FlushConsoleInputBuffer(fileDesc uintptr) error {
+	return withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(flushConsoleInputBufferProc, fd)
+		return err
+	})
+}
 
-	CONSOLE_SCREEN_BUFFER_INFO struct {
-		dwSize              COORD
-		dwCursorPosition    COORD
-		wAttributes         WORD
-		srWindow            SMALL_RECT
-		dwMaximumWindowSize COORD
+// fileNameInfo is the FileNameInfo class of GetFileInformationByHandleEx
+// see http://msdn.microsoft.com/en-us/library/windows/desktop/aa364388(v=vs.85).aspx
+const fileNameInfo = 2
+
+// FILE_NAME_INFO, with room for a name of MAX_PATH characters
+type fileNameInformation struct {
+	FileNameLength uint32
+	FileName       [syscall.MAX_PATH]uint16
+}
+
+// GetFileName returns the name of the file or pipe behind the given handle,
+// relative to its volume or device.
+func GetFileName(fileDesc uintptr) (string, error) {
+	var info fileNameInformation
+	err := withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(getFileInformationByHandleExProc, fd, fileNameInfo, uintptr(unsafe.Pointer(&info)), unsafe.Sizeof(info))
+		return err
+	})
+	if err != nil {
+		return "", err
 	}
+	n := int(info.FileNameLength / 2)
+	if n > len(info.FileName) {
+		n = len(info.FileName)
+	}
+	return syscall.UTF16ToString(info.FileName[:n]), nil
+}
+
+// types for calling GetCurrentConsoleFontEx
+// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms682069(v=vs.85).aspx
+const (
+	LF_FACESIZE = 32
+
+	// FontFamily flags
+	TMPF_VECTOR   = 0x02
+	TMPF_TRUETYPE = 0x04
 )
 
-func GetConsoleScreenBufferInfo(fileDesc uintptr) (*CONSOLE_SCREEN_BUFFER_INFO, error) {
-	var info CONSOLE_SCREEN_BUFFER_INFO
-	r, _, err := getConsoleScreenBufferInfoProc.Call(uintptr(fileDesc), uintptr(unsafe.Pointer(&info)), 0)
-	if r == 0 {
-		if err != nil {
-			return nil, err
-		}
-		return nil, syscall.EINVAL
+type CONSOLE_FONT_INFOEX struct {
+	cbSize     uint32
+	nFont      uint32
+	dwFontSize COORD
+	FontFamily uint32
+	FontWeight uint32
+	FaceName   [LF_FACESIZE]uint16
+}
+
+// ConsoleFont describes the font used by a console window.
+type ConsoleFont struct {
+	FaceName string
+	// Width and Height are the size of a character cell in pixels.
+	Width  int
+	Height int
+	Family uint32
+	Weight uint32
+}
+
+// IsRaster returns true for raster fonts, such as the legacy "Terminal" font,
+// which can only display the characters of the OEM code page.
+func (f *ConsoleFont) IsRaster() bool {
+	return f.Family&(TMPF_VECTOR|TMPF_TRUETYPE) == 0
+}
+
+// GetConsoleFont returns the font of the console screen buffer behind the
+// given handle.
+func GetConsoleFont(fileDesc uintptr) (*ConsoleFont, error) {
+	var info CONSOLE_FONT_INFOEX
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	err := withHandle(fileDesc, func(fd uintptr) error {
+		_, err := callProc(getCurrentConsoleFontExProc, fd, 0, uintptr(unsafe.Pointer(&info)))
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return &info, nil
+	return &ConsoleFont{
+		FaceName: syscall.UTF16ToString(info.FaceName[:]),
+		Width:    int(info.dwFontSize.X),
+		Height:   int(info.dwFontSize.Y),
+		Family:   info.FontFamily,
+		Weight:   info.FontWeight,
+	}, nil
 }
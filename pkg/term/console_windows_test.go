@@ -0,0 +1,59 @@
+// +build windows
+
+package term
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func TestCoordToUintptr(t *testing.T) {
+	for _, c := range []struct {
+		pos      COORD
+		expected uintptr
+	}{
+		{COORD{0, 0}, 0},
+		{COORD{80, 25}, 25<<16 | 80},
+		{COORD{0x7fff, 0x7fff}, 0x7fff7fff},
+		{COORD{-1, 0}, 0x0000ffff},
+		{COORD{0, -1}, 0xffff0000},
+	} {
+		if actual := coordToUintptr(c.pos); actual != c.expected {
+			t.Errorf("coordToUintptr(%v) = %#x, expected %#x", c.pos, actual, c.expected)
+		}
+		// The value must have the same layout as the struct in memory, as
+		// the API reads it as a COORD from the argument register or slot.
+		pos := c.pos
+		if inMemory := uintptr(*(*uint32)(unsafe.Pointer(&pos))); inMemory != c.expected {
+			t.Errorf("COORD %v is laid out as %#x in memory, expected %#x", c.pos, inMemory, c.expected)
+		}
+	}
+}
+
+func TestCallProcMissing(t *testing.T) {
+	proc := kernel32DLL.NewProc("NoSuchConsoleFunction")
+	for i := 0; i < 2; i++ {
+		_, err := callProc(proc)
+		if perr, ok := err.(*ProcError); !ok || perr.Name != "NoSuchConsoleFunction" {
+			t.Fatalf("callProc on a missing function returned %v, expected a ProcError", err)
+		}
+	}
+}
+
+func TestHRESULTError(t *testing.T) {
+	// E_ACCESSDENIED wraps the Win32 ERROR_ACCESS_DENIED
+	if errno, ok := HRESULTError(0x80070005).Errno(); !ok || errno != syscall.ERROR_ACCESS_DENIED {
+		t.Fatalf("Errno() = %v, %v, expected ERROR_ACCESS_DENIED", errno, ok)
+	}
+	if msg, expected := HRESULTError(0x80070005).Error(), syscall.ERROR_ACCESS_DENIED.Error(); msg != expected {
+		t.Fatalf("Error() = %q, expected %q", msg, expected)
+	}
+	// E_FAIL
+	if _, ok := HRESULTError(0x80004005).Errno(); ok {
+		t.Fatal("E_FAIL doesn't wrap a Win32 error code")
+	}
+	if msg := HRESULTError(0x80004005).Error(); msg != "HRESULT 0x80004005" {
+		t.Fatalf("Error() = %q", msg)
+	}
+}
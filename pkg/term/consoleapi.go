@@ -0,0 +1,64 @@
+package term
+
+import "syscall"
+
+// consoleAPI is the part of the console API used by logic that is kept apart
+// from the system calls, so that it can be tested with a fake console on any
+// platform. On Windows, kernel32Console implements it.
+type consoleAPI interface {
+	GetConsoleScreenBufferInfo(fd uintptr) (*CONSOLE_SCREEN_BUFFER_INFO, error)
+	SetConsoleScreenBufferSize(fd uintptr, size COORD) error
+	SetConsoleWindowInfo(fd uintptr, window SMALL_RECT) error
+}
+
+// setConsoleWinsize implements SetWinsize on api.
+func setConsoleWinsize(api consoleAPI, fd uintptr, ws *Winsize) error {
+	if ws.Width == 0 || ws.Height == 0 || ws.Width > maxShort || ws.Height > maxShort {
+		return syscall.EINVAL
+	}
+	info, err := api.GetConsoleScreenBufferInfo(fd)
+	if err != nil {
+		return err
+	}
+	width, height := SHORT(ws.Width), SHORT(ws.Height)
+
+	size := COORD{X: width, Y: info.dwSize.Y}
+	if size.Y < height {
+		size.Y = height
+	}
+
+	top := info.srWindow.Top
+	if top+height > size.Y {
+		top = size.Y - height
+	}
+	window := WindowFromWinsize(ws, top)
+
+	// The window must fit in the buffer at all times: growing needs the
+	// buffer to be resized first, shrinking the window first. Handle both
+	// at once by first shrinking the window to what fits in the old and
+	// the new buffer.
+	current := info.srWindow
+	if currentSize := WindowSize(current); currentSize.X > width {
+		current.Right = current.Left + width - 1
+	}
+	if currentSize := WindowSize(current); currentSize.Y > height {
+		current.Bottom = current.Top + height - 1
+	}
+	if current.Right >= size.X {
+		current.Right, current.Left = size.X-1, size.X-1-(current.Right-current.Left)
+	}
+	if current.Bottom >= size.Y {
+		current.Bottom, current.Top = size.Y-1, size.Y-1-(current.Bottom-current.Top)
+	}
+	if current != info.srWindow {
+		if err := api.SetConsoleWindowInfo(fd, current); err != nil {
+			return err
+		}
+	}
+	if size != info.dwSize {
+		if err := api.SetConsoleScreenBufferSize(fd, size); err != nil {
+			return err
+		}
+	}
+	return api.SetConsoleWindowInfo(fd, window)
+}
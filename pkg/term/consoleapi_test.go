@@ -0,0 +1,118 @@
+package term
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+)
+
+// fakeConsole is a consoleAPI that records the calls made to it and, like the
+// actual console, rejects a window that doesn't fit in the screen buffer.
+type fakeConsole struct {
+	info  CONSOLE_SCREEN_BUFFER_INFO
+	calls []string
+}
+
+func (c *fakeConsole) GetConsoleScreenBufferInfo(fd uintptr) (*CONSOLE_SCREEN_BUFFER_INFO, error) {
+	info := c.info
+	return &info, nil
+}
+
+func (c *fakeConsole) SetConsoleScreenBufferSize(fd uintptr, size COORD) error {
+	c.calls = append(c.calls, fmt.Sprintf("buffer %dx%d", size.X, size.Y))
+	if c.info.srWindow.Right >= size.X || c.info.srWindow.Bottom >= size.Y {
+		return syscall.EINVAL
+	}
+	c.info.dwSize = size
+	return nil
+}
+
+func (c *fakeConsole) SetConsoleWindowInfo(fd uintptr, window SMALL_RECT) error {
+	c.calls = append(c.calls, fmt.Sprintf("window %d,%d-%d,%d", window.Left, window.Top, window.Right, window.Bottom))
+	if window.Left < 0 || window.Top < 0 || window.Right >= c.info.dwSize.X || window.Bottom >= c.info.dwSize.Y {
+		return syscall.EINVAL
+	}
+	c.info.srWindow = window
+	return nil
+}
+
+func TestSetConsoleWinsize(t *testing.T) {
+	tests := []struct {
+		name          string
+		buffer        COORD
+		window        SMALL_RECT
+		ws            Winsize
+		expectedCalls []string
+	}{
+		{
+			name:   "grow",
+			buffer: COORD{80, 300},
+			window: SMALL_RECT{0, 100, 79, 124},
+			ws:     Winsize{Width: 120, Height: 40},
+			expectedCalls: []string{
+				"buffer 120x300",
+				"window 0,100-119,139",
+			},
+		},
+		{
+			name:   "shrink",
+			buffer: COORD{120, 300},
+			window: SMALL_RECT{0, 100, 119, 139},
+			ws:     Winsize{Width: 80, Height: 25},
+			expectedCalls: []string{
+				"window 0,100-79,124",
+				"buffer 80x300",
+				"window 0,100-79,124",
+			},
+		},
+		{
+			name:   "taller than the buffer",
+			buffer: COORD{80, 25},
+			window: SMALL_RECT{0, 0, 79, 24},
+			ws:     Winsize{Width: 100, Height: 50},
+			expectedCalls: []string{
+				"buffer 100x50",
+				"window 0,0-99,49",
+			},
+		},
+		{
+			name:   "window at the bottom of the buffer",
+			buffer: COORD{120, 300},
+			window: SMALL_RECT{40, 260, 119, 299},
+			ws:     Winsize{Width: 100, Height: 50},
+			expectedCalls: []string{
+				"window 20,260-99,299",
+				"buffer 100x300",
+				"window 0,250-99,299",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		c := &fakeConsole{}
+		c.info.dwSize, c.info.srWindow = test.buffer, test.window
+		if err := setConsoleWinsize(c, 0, &test.ws); err != nil {
+			t.Errorf("%s: %v (calls: %q)", test.name, err, c.calls)
+			continue
+		}
+		if fmt.Sprint(c.calls) != fmt.Sprint(test.expectedCalls) {
+			t.Errorf("%s: expected calls %q, got %q", test.name, test.expectedCalls, c.calls)
+		}
+		if size := WindowSize(c.info.srWindow); size != (COORD{SHORT(test.ws.Width), SHORT(test.ws.Height)}) {
+			t.Errorf("%s: window is %dx%d", test.name, size.X, size.Y)
+		}
+	}
+}
+
+func TestSetConsoleWinsizeInvalid(t *testing.T) {
+	c := &fakeConsole{}
+	if err := setConsoleWinsize(c, 0, &Winsize{Width: 80}); err != syscall.EINVAL {
+		t.Fatalf("expected EINVAL for a zero height, got %v", err)
+	}
+	if err := setConsoleWinsize(c, 0, &Winsize{Width: 40000, Height: 25}); err != syscall.EINVAL {
+		t.Fatalf("expected EINVAL for a width beyond the console coordinates, got %v", err)
+	}
+	if len(c.calls) != 0 {
+		t.Fatalf("unexpected calls: %q", c.calls)
+	}
+}
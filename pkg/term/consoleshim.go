@@ -0,0 +1,73 @@
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrNotConsole is returned by NewConsole for files that aren't terminals.
+var ErrNotConsole = errors.New("The file is not a terminal")
+
+// Console is a terminal with the methods of the Console interface of
+// github.com/containerd/console, for components built around that interface.
+// Sizes are Winsize values rather than console.WinSize ones, so a
+// one-line conversion is still needed where the interface types meet.
+type Console struct {
+	*os.File
+	// state is the state of the terminal when the Console was created,
+	// restored by Reset.
+	state *State
+}
+
+// NewConsole returns a Console for f, which must be a terminal.
+func NewConsole(f *os.File) (*Console, error) {
+	fd := f.Fd()
+	if !IsTerminal(fd) {
+		return nil, ErrNotConsole
+	}
+	state, err := SaveState(fd)
+	if err != nil {
+		return nil, err
+	}
+	return &Console{File: f, state: state}, nil
+}
+
+// Resize sets the size of the terminal.
+func (c *Console) Resize(ws Winsize) error {
+	return SetWinsize(c.Fd(), &ws)
+}
+
+// ResizeFrom sets the size of the terminal to the size of src.
+func (c *Console) ResizeFrom(src *Console) error {
+	ws, err := src.Size()
+	if err != nil {
+		return err
+	}
+	return c.Resize(ws)
+}
+
+// SetRaw puts the terminal into raw mode.
+func (c *Console) SetRaw() error {
+	_, err := MakeRaw(c.Fd())
+	return err
+}
+
+// DisableEcho turns off the echo of input, leaving the rest of the mode of the
+// terminal as it is.
+func (c *Console) DisableEcho() error {
+	return disableEcho(c.Fd())
+}
+
+// Reset restores the state the terminal was in when NewConsole was called.
+func (c *Console) Reset() error {
+	return RestoreTerminal(c.Fd(), c.state)
+}
+
+// Size returns the size of the terminal.
+func (c *Console) Size() (Winsize, error) {
+	ws, err := GetWinsize(c.Fd())
+	if err != nil {
+		return Winsize{}, err
+	}
+	return *ws, nil
+}
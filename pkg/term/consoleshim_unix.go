@@ -0,0 +1,14 @@
+// +build !windows
+
+package term
+
+import "syscall"
+
+func disableEcho(fd uintptr) error {
+	termios, err := Tcgetattr(fd)
+	if err != nil {
+		return err
+	}
+	termios.Lflag &^= syscall.ECHO
+	return Tcsetattr(fd, termios)
+}
@@ -0,0 +1,8 @@
+// +build windows
+
+package term
+
+func disableEcho(fd uintptr) error {
+	_, err := SetConsoleModeOptions(fd, WithEcho(false))
+	return err
+}
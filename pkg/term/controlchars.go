@@ -0,0 +1,13 @@
+package term
+
+// ControlChars are the characters a terminal interprets for line editing in
+// canonical mode, which line-editing helpers should handle the same way in
+// raw mode.
+type ControlChars struct {
+	// Erase deletes the previous character, usually ^? or ^H.
+	Erase byte
+	// Kill deletes the whole line, usually ^U.
+	Kill byte
+	// EOF ends the input, usually ^D.
+	EOF byte
+}
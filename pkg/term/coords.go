@@ -0,0 +1,77 @@
+package term
+
+// WindowSize returns the size of a console window rectangle.
+func WindowSize(window SMALL_RECT) COORD {
+	return COORD{X: window.Right - window.Left + 1, Y: window.Bottom - window.Top + 1}
+}
+
+// BufferToWindow converts a position in screen buffer coordinates to a
+// position relative to the top left corner of the window.
+func BufferToWindow(window SMALL_RECT, pos COORD) COORD {
+	return COORD{X: pos.X - window.Left, Y: pos.Y - window.Top}
+}
+
+// WindowToBuffer converts a position relative to the top left corner of the
+// window to screen buffer coordinates.
+func WindowToBuffer(window SMALL_RECT, pos COORD) COORD {
+	return COORD{X: pos.X + window.Left, Y: pos.Y + window.Top}
+}
+
+// IsInWindow returns true if pos, in screen buffer coordinates, is visible in
+// the window.
+func IsInWindow(window SMALL_RECT, pos COORD) bool {
+	return pos.X >= window.Left && pos.X <= window.Right && pos.Y >= window.Top && pos.Y <= window.Bottom
+}
+
+// ClampToBuffer returns pos moved to the nearest position within a screen
+// buffer of the given size.
+func ClampToBuffer(size COORD, pos COORD) COORD {
+	return COORD{X: clampShort(pos.X, 0, size.X-1), Y: clampShort(pos.Y, 0, size.Y-1)}
+}
+
+// ClampToWindow returns pos, in screen buffer coordinates, moved to the nearest
+// position visible in the window.
+func ClampToWindow(window SMALL_RECT, pos COORD) COORD {
+	return COORD{X: clampShort(pos.X, window.Left, window.Right), Y: clampShort(pos.Y, window.Top, window.Bottom)}
+}
+
+func clampShort(v, min, max SHORT) SHORT {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// WinsizeFromWindow returns the size of a console window rectangle as a
+// Winsize.
+func WinsizeFromWindow(window SMALL_RECT) *Winsize {
+	size := WindowSize(window)
+	return &Winsize{Width: uint16(size.X), Height: uint16(size.Y)}
+}
+
+// WindowFromWinsize returns the console window rectangle of size ws whose top
+// row is the given line of the screen buffer and which starts at the left edge
+// of the buffer.
+func WindowFromWinsize(ws *Winsize, top SHORT) SMALL_RECT {
+	return SMALL_RECT{
+		Left:   0,
+		Top:    top,
+		Right:  toShort(ws.Width) - 1,
+		Bottom: top + toShort(ws.Height) - 1,
+	}
+}
+
+// maxShort is the largest coordinate of a console screen buffer.
+const maxShort = 1<<15 - 1
+
+// toShort converts a Winsize dimension to a console coordinate, clamping it
+// rather than wrapping to a negative value.
+func toShort(v uint16) SHORT {
+	if v > maxShort {
+		return maxShort
+	}
+	return SHORT(v)
+}
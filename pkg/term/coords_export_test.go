@@ -0,0 +1,42 @@
+package term_test
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// TestCoordHelpersExported uses the coordinate helpers as downstream code
+// driving the console directly would.
+func TestCoordHelpersExported(t *testing.T) {
+	// an 80x25 window scrolled down to line 100 of a 120x300 buffer
+	window := term.SMALL_RECT{Left: 0, Top: 100, Right: 79, Bottom: 124}
+
+	if size := term.WindowSize(window); size != (term.COORD{X: 80, Y: 25}) {
+		t.Fatalf("WindowSize = %v", size)
+	}
+	pos := term.COORD{X: 10, Y: 110}
+	rel := term.BufferToWindow(window, pos)
+	if rel != (term.COORD{X: 10, Y: 10}) {
+		t.Fatalf("BufferToWindow = %v", rel)
+	}
+	if back := term.WindowToBuffer(window, rel); back != pos {
+		t.Fatalf("WindowToBuffer = %v, expected %v", back, pos)
+	}
+	if !term.IsInWindow(window, pos) || term.IsInWindow(window, term.COORD{X: 10, Y: 99}) {
+		t.Fatal("IsInWindow returned a wrong result")
+	}
+	if c := term.ClampToWindow(window, term.COORD{X: 200, Y: 0}); c != (term.COORD{X: 79, Y: 100}) {
+		t.Fatalf("ClampToWindow = %v", c)
+	}
+	if c := term.ClampToBuffer(term.COORD{X: 120, Y: 300}, term.COORD{X: -3, Y: 400}); c != (term.COORD{X: 0, Y: 299}) {
+		t.Fatalf("ClampToBuffer = %v", c)
+	}
+	ws := term.WinsizeFromWindow(window)
+	if ws.Width != 80 || ws.Height != 25 {
+		t.Fatalf("WinsizeFromWindow = %v", ws)
+	}
+	if w := term.WindowFromWinsize(ws, 100); w != window {
+		t.Fatalf("WindowFromWinsize = %v, expected %v", w, window)
+	}
+}
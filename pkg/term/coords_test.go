@@ -0,0 +1,47 @@
+package term
+
+import "testing"
+
+func TestCoordTranslation(t *testing.T) {
+	// an 80x25 window scrolled down to line 100 of a 120x300 buffer
+	window := SMALL_RECT{Left: 10, Top: 100, Right: 89, Bottom: 124}
+
+	if size := WindowSize(window); size != (COORD{80, 25}) {
+		t.Fatalf("WindowSize = %v", size)
+	}
+	pos := COORD{15, 110}
+	rel := BufferToWindow(window, pos)
+	if rel != (COORD{5, 10}) {
+		t.Fatalf("BufferToWindow = %v", rel)
+	}
+	if back := WindowToBuffer(window, rel); back != pos {
+		t.Fatalf("WindowToBuffer = %v, expected %v", back, pos)
+	}
+	if !IsInWindow(window, pos) || IsInWindow(window, COORD{15, 99}) || IsInWindow(window, COORD{90, 110}) {
+		t.Fatal("IsInWindow returned a wrong result")
+	}
+	if c := ClampToWindow(window, COORD{0, 200}); c != (COORD{10, 124}) {
+		t.Fatalf("ClampToWindow = %v", c)
+	}
+	if c := ClampToBuffer(COORD{120, 300}, COORD{-3, 400}); c != (COORD{0, 299}) {
+		t.Fatalf("ClampToBuffer = %v", c)
+	}
+}
+
+func TestWinsizeWindowRoundTrip(t *testing.T) {
+	ws := &Winsize{Height: 25, Width: 80}
+	window := WindowFromWinsize(ws, 100)
+	if expected := (SMALL_RECT{Left: 0, Top: 100, Right: 79, Bottom: 124}); window != expected {
+		t.Fatalf("WindowFromWinsize = %v, expected %v", window, expected)
+	}
+	if back := WinsizeFromWindow(window); *back != *ws {
+		t.Fatalf("WinsizeFromWindow = %v, expected %v", back, ws)
+	}
+}
+
+func TestWindowFromWinsizeClamps(t *testing.T) {
+	window := WindowFromWinsize(&Winsize{Height: 25, Width: 40000}, 0)
+	if expected := (SMALL_RECT{Left: 0, Top: 0, Right: maxShort - 1, Bottom: 24}); window != expected {
+		t.Fatalf("WindowFromWinsize = %v, expected %v", window, expected)
+	}
+}
@@ -0,0 +1,43 @@
+package term
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Counters are totals of the work done by this package since the process
+// started, to be included in bug reports and performance investigations.
+// Their String method returns them as JSON, so that they can be published
+// with expvar.
+type Counters struct {
+	// ConsoleCalls is the number of console API functions called on
+	// Windows.
+	ConsoleCalls int64
+	// SequencesTranslated is the number of complete escape sequences handled
+	// by the Linux console writer, whether changed, dropped or kept as is.
+	SequencesTranslated int64
+	// SequencesDropped is the number of escape sequences the Linux console
+	// writer dropped, because the console doesn't support them or because
+	// they were too long.
+	SequencesDropped int64
+	// BytesWritten is the number of bytes written by the Linux console
+	// writer to its underlying writer.
+	BytesWritten int64
+}
+
+var counters Counters
+
+// GetCounters returns a snapshot of the counters.
+func GetCounters() Counters {
+	return Counters{
+		ConsoleCalls:        atomic.LoadInt64(&counters.ConsoleCalls),
+		SequencesTranslated: atomic.LoadInt64(&counters.SequencesTranslated),
+		SequencesDropped:    atomic.LoadInt64(&counters.SequencesDropped),
+		BytesWritten:        atomic.LoadInt64(&counters.BytesWritten),
+	}
+}
+
+func (c Counters) String() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
@@ -0,0 +1,55 @@
+package term
+
+import (
+	"bytes"
+	"io"
+)
+
+// NewCRLFWriter returns a writer translating bare line feeds written to it
+// into CR LF, as the ONLCR flag of a tty does, for output to a terminal that
+// doesn't do it itself, such as the Windows console with
+// ENABLE_PROCESSED_OUTPUT turned off, where raw output from a container would
+// otherwise be displayed as stairs. Line feeds already preceded by a carriage
+// return are left alone.
+func NewCRLFWriter(w io.Writer) io.Writer {
+	return &crlfWriter{w: w}
+}
+
+type crlfWriter struct {
+	w io.Writer
+	// cr is set when the last byte written was a carriage return.
+	cr  bool
+	out bytes.Buffer
+}
+
+func (c *crlfWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := len(p)
+	out := &c.out
+	out.Reset()
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			out.Write(p)
+			c.cr = p[len(p)-1] == '\r'
+			break
+		}
+		out.Write(p[:i])
+		if i > 0 && p[i-1] != '\r' || i == 0 && !c.cr {
+			out.WriteByte('\r')
+		}
+		out.WriteByte('\n')
+		c.cr = false
+		p = p[i+1:]
+	}
+
+	if _, err := c.w.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	if out.Cap() > maxRetainedOutput {
+		c.out = bytes.Buffer{}
+	}
+	return n, nil
+}
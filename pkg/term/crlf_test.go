@@ -0,0 +1,32 @@
+package term
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCRLFWriter(t *testing.T) {
+	for _, test := range []struct {
+		writes   []string
+		expected string
+	}{
+		{[]string{"no newline"}, "no newline"},
+		{[]string{"a\nb\n"}, "a\r\nb\r\n"},
+		{[]string{"\n\n"}, "\r\n\r\n"},
+		{[]string{"a\r\nb\r\n"}, "a\r\nb\r\n"},
+		{[]string{"a\r", "\nb\n"}, "a\r\nb\r\n"},
+		{[]string{"a", "\n"}, "a\r\n"},
+		{[]string{"progress\r50%\n"}, "progress\r50%\r\n"},
+	} {
+		var buf bytes.Buffer
+		w := NewCRLFWriter(&buf)
+		for _, s := range test.writes {
+			if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+				t.Fatalf("%q: Write returned %d, %v", test.writes, n, err)
+			}
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.writes, test.expected, buf.String())
+		}
+	}
+}
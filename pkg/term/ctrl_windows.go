@@ -0,0 +1,122 @@
+// +build windows
+
+package term
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ConsoleEvent is a control event sent by the console to the processes
+// attached to it.
+// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms683242(v=vs.85).aspx
+type ConsoleEvent uint32
+
+const (
+	CTRL_C_EVENT        ConsoleEvent = 0
+	CTRL_BREAK_EVENT    ConsoleEvent = 1
+	CTRL_CLOSE_EVENT    ConsoleEvent = 2
+	CTRL_LOGOFF_EVENT   ConsoleEvent = 5
+	CTRL_SHUTDOWN_EVENT ConsoleEvent = 6
+)
+
+// After a close, logoff or shutdown event the process is terminated as soon as
+// the handler returns, so the handler waits this long for the receivers to
+// clean up and exit. Windows kills the process after 5 seconds anyway.
+const consoleExitGracePeriod = 4 * time.Second
+
+var setConsoleCtrlHandlerProc = kernel32DLL.NewProc("SetConsoleCtrlHandler")
+
+var consoleEvents = struct {
+	sync.Mutex
+	handler uintptr
+	// channels maps the registered channels to the events they receive,
+	// nil meaning all of them.
+	channels map[chan<- ConsoleEvent][]ConsoleEvent
+}{channels: make(map[chan<- ConsoleEvent][]ConsoleEvent)}
+
+func (e ConsoleEvent) String() string {
+	switch e {
+	case CTRL_C_EVENT:
+		return "CTRL_C_EVENT"
+	case CTRL_BREAK_EVENT:
+		return "CTRL_BREAK_EVENT"
+	case CTRL_CLOSE_EVENT:
+		return "CTRL_CLOSE_EVENT"
+	case CTRL_LOGOFF_EVENT:
+		return "CTRL_LOGOFF_EVENT"
+	case CTRL_SHUTDOWN_EVENT:
+		return "CTRL_SHUTDOWN_EVENT"
+	}
+	return fmt.Sprintf("ConsoleEvent(%d)", uint32(e))
+}
+
+// NotifyConsoleEvents causes the given console control events, or all of them
+// if none is given, to be relayed to c, in the same fashion as signal.Notify:
+// sends do not block, so c should be buffered. While a channel is registered
+// for an event, the event is no longer handled by the default handlers
+// (which, for Go programs, turn them into os.Interrupt and SIGTERM).
+func NotifyConsoleEvents(c chan<- ConsoleEvent, events ...ConsoleEvent) error {
+	consoleEvents.Lock()
+	defer consoleEvents.Unlock()
+
+	if consoleEvents.handler == 0 {
+		handler := syscall.NewCallback(handleConsoleEvent)
+		if _, err := callProc(setConsoleCtrlHandlerProc, handler, 1); err != nil {
+			return err
+		}
+		consoleEvents.handler = handler
+	}
+	consoleEvents.channels[c] = events
+	return nil
+}
+
+// StopConsoleEvents stops relaying console control events to c.
+func StopConsoleEvents(c chan<- ConsoleEvent) {
+	consoleEvents.Lock()
+	delete(consoleEvents.channels, c)
+	consoleEvents.Unlock()
+}
+
+// handleConsoleEvent is the HandlerRoutine registered with
+// SetConsoleCtrlHandler. It runs on a thread created by the system.
+func handleConsoleEvent(event uintptr) uintptr {
+	consoleEvents.Lock()
+	handled := false
+	for c, events := range consoleEvents.channels {
+		if !wantsEvent(events, ConsoleEvent(event)) {
+			continue
+		}
+		handled = true
+		select {
+		case c <- ConsoleEvent(event):
+		default:
+		}
+	}
+	consoleEvents.Unlock()
+
+	if !handled {
+		// let the next handler in the chain process it
+		return 0
+	}
+	switch ConsoleEvent(event) {
+	case CTRL_CLOSE_EVENT, CTRL_LOGOFF_EVENT, CTRL_SHUTDOWN_EVENT:
+		time.Sleep(consoleExitGracePeriod)
+	}
+	return 1
+}
+
+// wantsEvent returns true if event is one of events, or if events is empty.
+func wantsEvent(events []ConsoleEvent, event ConsoleEvent) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
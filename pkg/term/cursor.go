@@ -0,0 +1,112 @@
+// +build !windows
+
+package term
+
+import (
+	"errors"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// cursorQueryTimeout is how long GetCursorPosition waits for the terminal to
+// answer.
+var cursorQueryTimeout = time.Second
+
+var ErrCursorQueryTimeout = errors.New("The terminal did not report the cursor position")
+
+// GetCursorPosition returns the position of the cursor, counted from 0 at the
+// top left corner of the terminal connected to the given file descriptor,
+// which must be open for reading and writing. It asks the terminal with a
+// Device Status Report, so input typed while waiting for the answer is lost.
+func GetCursorPosition(fd uintptr) (x, y int, err error) {
+	oldState, err := SaveState(fd)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer RestoreTerminal(fd, oldState)
+
+	termios := oldState.termios
+	termios.Lflag &^= (syscall.ICANON | syscall.ECHO)
+	termios.Cc[syscall.VMIN] = 0
+	termios.Cc[syscall.VTIME] = 1
+	if err := Tcsetattr(fd, &termios); err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := syscall.Write(int(fd), []byte("\x1b[6n")); err != nil {
+		return 0, 0, err
+	}
+
+	var answer []byte
+	buf := make([]byte, 32)
+	deadline := time.Now().Add(cursorQueryTimeout)
+	for time.Now().Before(deadline) {
+		n, err := syscall.Read(int(fd), buf)
+		if err != nil && err != syscall.EAGAIN && err != syscall.EINTR {
+			return 0, 0, err
+		}
+		if n > 0 {
+			answer = append(answer, buf[:n]...)
+			if row, col, ok := parseCursorPositionReport(answer); ok {
+				return col - 1, row - 1, nil
+			}
+		}
+	}
+	return 0, 0, ErrCursorQueryTimeout
+}
+
+// parseCursorPositionReport looks for a Cursor Position Report, ESC [ row ; col
+// R, at the end of data.
+func parseCursorPositionReport(data []byte) (row, col int, ok bool) {
+	if len(data) == 0 || data[len(data)-1] != 'R' {
+		return 0, 0, false
+	}
+	i := len(data) - 2
+	for ; i >= 0 && data[i] >= '0' && data[i] <= '9'; i-- {
+	}
+	colStart := i + 1
+	if i < 0 || data[i] != ';' || colStart == len(data)-1 {
+		return 0, 0, false
+	}
+	for i--; i >= 0 && data[i] >= '0' && data[i] <= '9'; i-- {
+	}
+	rowStart := i + 1
+	if i < 1 || data[i] != '[' || data[i-1] != '\x1b' || rowStart == colStart-1 {
+		return 0, 0, false
+	}
+	row, _ = strconv.Atoi(string(data[rowStart : colStart-1]))
+	col, _ = strconv.Atoi(string(data[colStart : len(data)-1]))
+	return row, col, true
+}
+
+// setCursorVisible shows or hides the cursor of the terminal.
+func setCursorVisible(fd uintptr, visible bool) error {
+	seq := "\x1b[?25l"
+	if visible {
+		seq = "\x1b[?25h"
+	}
+	_, err := syscall.Write(int(fd), []byte(seq))
+	return err
+}
+
+// HideCursor hides the cursor of the terminal connected to the given file
+// descriptor, e.g. while drawing progress bars. The cursor is shown again by
+// ShowCursor, or by RestoreAll if the process dies first.
+func HideCursor(fd uintptr) error {
+	if err := setCursorVisible(fd, false); err != nil {
+		return err
+	}
+	setCursorHidden(fd, true)
+	return nil
+}
+
+// ShowCursor shows the cursor of the terminal connected to the given file
+// descriptor.
+func ShowCursor(fd uintptr) error {
+	if err := setCursorVisible(fd, true); err != nil {
+		return err
+	}
+	setCursorHidden(fd, false)
+	return nil
+}
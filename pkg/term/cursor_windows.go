@@ -0,0 +1,88 @@
+// +build windows
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// GetCursorPosition returns the position of the cursor, counted from 0 at the
+// top left corner of the console window connected to the given handle.
+func GetCursorPosition(fd uintptr) (x, y int, err error) {
+	info, err := GetConsoleScreenBufferInfo(fd)
+	if err != nil {
+		return 0, 0, err
+	}
+	pos := BufferToWindow(info.srWindow, info.dwCursorPosition)
+	return int(pos.X), int(pos.Y), nil
+}
+
+// CONSOLE_CURSOR_INFO is used by GetConsoleCursorInfo and SetConsoleCursorInfo.
+// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms682068(v=vs.85).aspx
+type CONSOLE_CURSOR_INFO struct {
+	dwSize   uint32
+	bVisible int32
+}
+
+var (
+	getConsoleCursorInfoProc = kernel32DLL.NewProc("GetConsoleCursorInfo")
+	setConsoleCursorInfoProc = kernel32DLL.NewProc("SetConsoleCursorInfo")
+)
+
+func GetConsoleCursorInfo(fd uintptr) (*CONSOLE_CURSOR_INFO, error) {
+	info := &CONSOLE_CURSOR_INFO{}
+	if _, err := callProc(getConsoleCursorInfoProc, fd, uintptr(unsafe.Pointer(info))); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func SetConsoleCursorInfo(fd uintptr, info *CONSOLE_CURSOR_INFO) error {
+	if _, err := callProc(setConsoleCursorInfoProc, fd, uintptr(unsafe.Pointer(info))); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setCursorVisible shows or hides the cursor of the console, or of the
+// terminal emulator behind a Cygwin pty.
+func setCursorVisible(fd uintptr, visible bool) error {
+	if IsCygwinTerminal(fd) {
+		seq := "\x1b[?25l"
+		if visible {
+			seq = "\x1b[?25h"
+		}
+		_, err := syscall.Write(syscall.Handle(fd), []byte(seq))
+		return err
+	}
+	info, err := GetConsoleCursorInfo(fd)
+	if err != nil {
+		return err
+	}
+	info.bVisible = 0
+	if visible {
+		info.bVisible = 1
+	}
+	return SetConsoleCursorInfo(fd, info)
+}
+
+// HideCursor hides the cursor of the console connected to the given handle,
+// e.g. while drawing progress bars. The cursor is shown again by ShowCursor,
+// or by RestoreAll if the process dies first.
+func HideCursor(fd uintptr) error {
+	if err := setCursorVisible(fd, false); err != nil {
+		return err
+	}
+	setCursorHidden(fd, true)
+	return nil
+}
+
+// ShowCursor shows the cursor of the console connected to the given handle.
+func ShowCursor(fd uintptr) error {
+	if err := setCursorVisible(fd, true); err != nil {
+		return err
+	}
+	setCursorHidden(fd, false)
+	return nil
+}
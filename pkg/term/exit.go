@@ -0,0 +1,79 @@
+package term
+
+import "sync"
+
+var exitStates = struct {
+	sync.Mutex
+	states  map[uintptr]*State
+	cursors map[uintptr]bool
+	// stop stops the notification of fatal signals, installed while
+	// states are registered.
+	stop func()
+}{states: make(map[uintptr]*State), cursors: make(map[uintptr]bool)}
+
+// RestoreOnExit registers state to be restored on the terminal connected to
+// the given file descriptor if the process is killed by a fatal signal,
+// SIGINT included (on Windows: its console is closed, or the user logs off),
+// or panics in a function that deferred RestoreOnPanic. This way the terminal
+// isn't left in raw mode when the process dies unexpectedly. The returned function
+// unregisters state, once it has been restored normally; when no state is left,
+// the signals and console events are handled as usual again.
+func RestoreOnExit(fd uintptr, state *State) func() {
+	exitStates.Lock()
+	defer exitStates.Unlock()
+
+	if exitStates.stop == nil {
+		exitStates.stop = notifyFatal(func() {
+			RestoreAll()
+		})
+	}
+	exitStates.states[fd] = state
+
+	return func() {
+		exitStates.Lock()
+		defer exitStates.Unlock()
+		if exitStates.states[fd] == state {
+			delete(exitStates.states, fd)
+		}
+		if len(exitStates.states) == 0 && exitStates.stop != nil {
+			exitStates.stop()
+			exitStates.stop = nil
+		}
+	}
+}
+
+// RestoreAll restores all the states registered with RestoreOnExit, and shows
+// the cursors hidden with HideCursor.
+func RestoreAll() {
+	exitStates.Lock()
+	defer exitStates.Unlock()
+
+	for fd, state := range exitStates.states {
+		RestoreTerminal(fd, state)
+	}
+	for fd := range exitStates.cursors {
+		setCursorVisible(fd, true)
+	}
+}
+
+// setCursorHidden records whether the cursor of the terminal connected to the
+// given file descriptor is hidden, for RestoreAll.
+func setCursorHidden(fd uintptr, hidden bool) {
+	exitStates.Lock()
+	defer exitStates.Unlock()
+
+	if hidden {
+		exitStates.cursors[fd] = true
+	} else {
+		delete(exitStates.cursors, fd)
+	}
+}
+
+// RestoreOnPanic, when deferred, restores all the states registered with
+// RestoreOnExit if the calling goroutine panics, then lets the panic go on.
+func RestoreOnPanic() {
+	if r := recover(); r != nil {
+		RestoreAll()
+		panic(r)
+	}
+}
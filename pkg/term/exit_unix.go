@@ -0,0 +1,34 @@
+// +build !windows
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyFatal calls restore when the process receives a signal that would
+// kill it, then kills it with the same signal. SIGINT is included for the
+// modes that leave it enabled, such as flow control being turned off; raw
+// mode doesn't generate it. The returned function stops the notification.
+func notifyFatal(restore func()) (stop func()) {
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-sigchan:
+			restore()
+			signal.Reset(sig)
+			syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigchan)
+		close(done)
+	}
+}
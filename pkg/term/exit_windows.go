@@ -0,0 +1,30 @@
+// +build windows
+
+package term
+
+import "os"
+
+// notifyFatal calls restore when the console of the process is closed or the
+// user logs off or shuts down, then exits. Other events, such as Ctrl+C, are
+// left to their usual handlers. The returned function stops the notification.
+func notifyFatal(restore func()) (stop func()) {
+	events := make(chan ConsoleEvent, 1)
+	if err := NotifyConsoleEvents(events, CTRL_CLOSE_EVENT, CTRL_LOGOFF_EVENT, CTRL_SHUTDOWN_EVENT); err != nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-events:
+			restore()
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		StopConsoleEvents(events)
+		close(done)
+	}
+}
@@ -0,0 +1,10 @@
+// +build !windows,!solaris
+
+package term
+
+import "syscall"
+
+func getFileFlags(fd uintptr) (int, syscall.Errno) {
+	flags, _, err := syscall.Syscall(syscall.SYS_FCNTL, fd, syscall.F_GETFL, 0)
+	return int(flags), err
+}
@@ -0,0 +1,29 @@
+// +build !windows
+
+package term
+
+import "errors"
+
+// ErrBackground is returned by the functions changing the mode of the
+// terminal when the process runs in the background, e.g. started with
+// `docker ... &`: the kernel would stop it with SIGTTOU instead.
+var ErrBackground = errors.New("The process is not in the foreground process group of the terminal")
+
+// IsForeground returns true unless the terminal connected to the given file
+// descriptor is the controlling terminal of the process and the process isn't
+// in its foreground process group.
+func IsForeground(fd uintptr) bool {
+	pgrp, err := tcgetpgrp(fd)
+	if err != 0 {
+		// not our controlling terminal, so no job control applies
+		return true
+	}
+	return pgrp == getpgrp()
+}
+
+func checkForeground(fd uintptr) error {
+	if !IsForeground(fd) {
+		return ErrBackground
+	}
+	return nil
+}
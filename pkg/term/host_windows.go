@@ -0,0 +1,94 @@
+// +build windows
+
+package term
+
+import (
+	"fmt"
+	"os"
+)
+
+// ENABLE_VIRTUAL_TERMINAL_PROCESSING makes the console interpret VT sequences
+// written to a screen buffer (Windows 10 and later).
+const ENABLE_VIRTUAL_TERMINAL_PROCESSING = 0x0004
+
+// ConsoleHost identifies the program displaying a console.
+type ConsoleHost int
+
+const (
+	HostUnknown ConsoleHost = iota
+	// HostLegacyConhost is conhost.exe without VT support, either from
+	// Windows versions before 10 or running in legacy mode.
+	HostLegacyConhost
+	// HostConhost is conhost.exe with VT support.
+	HostConhost
+	// HostWindowsTerminal is Windows Terminal.
+	HostWindowsTerminal
+	// HostThirdParty is a third-party host such as ConEmu or ANSICON.
+	HostThirdParty
+	// HostCygwin is a Cygwin or MSYS pty, e.g. mintty.
+	HostCygwin
+)
+
+func (h ConsoleHost) String() string {
+	switch h {
+	case HostLegacyConhost:
+		return "legacy conhost"
+	case HostConhost:
+		return "conhost"
+	case HostWindowsTerminal:
+		return "Windows Terminal"
+	case HostThirdParty:
+		return "third-party console host"
+	case HostCygwin:
+		return "Cygwin pty"
+	}
+	return "unknown"
+}
+
+// ConsoleHostInfo is the result of DetectConsoleHost.
+type ConsoleHostInfo struct {
+	Host ConsoleHost
+	// VT is set when the host interprets VT sequences itself.
+	VT bool
+	// Reason explains how the host was detected.
+	Reason string
+}
+
+// DetectConsoleHost finds out which program displays the console behind the
+// given output handle, from the environment it sets and by probing the console
+// modes it supports.
+func DetectConsoleHost(fd uintptr) *ConsoleHostInfo {
+	if IsCygwinTerminal(fd) {
+		return &ConsoleHostInfo{Host: HostCygwin, VT: true, Reason: "output is a Cygwin/MSYS pty pipe"}
+	}
+	mode, err := GetConsoleMode(fd)
+	if err != nil {
+		return &ConsoleHostInfo{Host: HostUnknown, Reason: fmt.Sprintf("output is not a console: %v", err)}
+	}
+	vt := probeVirtualTerminal(fd, mode)
+
+	switch {
+	case os.Getenv("WT_SESSION") != "":
+		return &ConsoleHostInfo{Host: HostWindowsTerminal, VT: vt, Reason: "WT_SESSION is set"}
+	case os.Getenv("ConEmuANSI") == "ON":
+		return &ConsoleHostInfo{Host: HostThirdParty, VT: true, Reason: "ConEmuANSI is ON"}
+	case os.Getenv("ANSICON") != "":
+		return &ConsoleHostInfo{Host: HostThirdParty, VT: true, Reason: "ANSICON is set"}
+	case vt:
+		return &ConsoleHostInfo{Host: HostConhost, VT: true, Reason: "console accepts ENABLE_VIRTUAL_TERMINAL_PROCESSING"}
+	}
+	return &ConsoleHostInfo{Host: HostLegacyConhost, Reason: "console rejects ENABLE_VIRTUAL_TERMINAL_PROCESSING"}
+}
+
+// probeVirtualTerminal returns true if the console accepts VT processing on
+// the given output handle, leaving its mode unchanged.
+func probeVirtualTerminal(fd uintptr, mode uint32) bool {
+	if mode&ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	if err := SetConsoleMode(fd, mode|ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		return false
+	}
+	SetConsoleMode(fd, mode)
+	return true
+}
@@ -0,0 +1,20 @@
+// +build !windows
+
+package term
+
+import (
+	"os"
+	"strings"
+)
+
+// isUTF8Locale returns true if the character set of the current locale, as
+// set by LC_ALL, LC_CTYPE or LANG, is UTF-8.
+func isUTF8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
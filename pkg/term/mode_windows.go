@@ -0,0 +1,115 @@
+// +build windows
+
+package term
+
+// ENABLE_VIRTUAL_TERMINAL_INPUT makes the console translate key presses to VT
+// sequences on an input handle (Windows 10 and later).
+const ENABLE_VIRTUAL_TERMINAL_INPUT = 0x0200
+
+// ConsoleModeOption changes one setting of a console mode. output is set when
+// the mode is the one of a screen buffer rather than of an input buffer.
+type ConsoleModeOption func(mode uint32, output bool) uint32
+
+func setModeFlag(mode, flag uint32, on bool) uint32 {
+	if on {
+		return mode | flag
+	}
+	return mode &^ flag
+}
+
+// WithEcho turns echoing of typed characters on or off. Echo only works with
+// line input enabled.
+func WithEcho(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_ECHO_INPUT, on)
+	}
+}
+
+// WithLineInput turns line buffering of input on or off.
+func WithLineInput(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_LINE_INPUT, on)
+	}
+}
+
+// WithProcessedInput turns the handling of Ctrl+C by the console on or off.
+func WithProcessedInput(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_PROCESSED_INPUT, on)
+	}
+}
+
+// WithMouse turns the reporting of mouse events as input on or off.
+func WithMouse(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_MOUSE_INPUT, on)
+	}
+}
+
+// WithWindowInput turns the reporting of buffer size changes as input on or
+// off.
+func WithWindowInput(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_WINDOW_INPUT, on)
+	}
+}
+
+// WithQuickEdit turns selecting text with the mouse on or off.
+func WithQuickEdit(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_QUICK_EDIT_MODE, on) | ENABLE_EXTENDED_FLAGS
+	}
+}
+
+// WithVT turns VT sequence support on or off: translation of key presses to VT
+// sequences for input buffers, interpretation of VT sequences for screen
+// buffers.
+func WithVT(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		if output {
+			return setModeFlag(mode, ENABLE_VIRTUAL_TERMINAL_PROCESSING, on)
+		}
+		return setModeFlag(mode, ENABLE_VIRTUAL_TERMINAL_INPUT, on)
+	}
+}
+
+// WithProcessedOutput turns the handling of control characters such as
+// backspace, tab and line feed by the console on or off.
+func WithProcessedOutput(on bool) ConsoleModeOption {
+	return func(mode uint32, output bool) uint32 {
+		return setModeFlag(mode, ENABLE_PROCESSED_OUTPUT, on)
+	}
+}
+
+// ApplyConsoleModeOptions returns mode changed by opts.
+func ApplyConsoleModeOptions(mode uint32, output bool, opts ...ConsoleModeOption) uint32 {
+	for _, opt := range opts {
+		mode = opt(mode, output)
+	}
+	return mode
+}
+
+// SetConsoleModeOptions changes the mode of the console input or screen buffer
+// connected to the given file descriptor and returns its previous state, to be
+// restored with RestoreTerminal.
+func SetConsoleModeOptions(fd uintptr, opts ...ConsoleModeOption) (*State, error) {
+	state, err := SaveState(fd)
+	if err != nil {
+		return nil, err
+	}
+	if state.cygwin {
+		return state, nil
+	}
+	_, err = GetConsoleScreenBufferInfo(fd)
+	output := err == nil
+
+	mode := ApplyConsoleModeOptions(state.mode, output, opts...)
+	if !output && state.mode&ENABLE_QUICK_EDIT_MODE != 0 {
+		// QuickEdit can only be turned back on along with ENABLE_EXTENDED_FLAGS
+		state.mode |= ENABLE_EXTENDED_FLAGS
+	}
+	if err := SetConsoleMode(fd, mode); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -0,0 +1,26 @@
+// +build windows
+
+package term
+
+import "testing"
+
+func TestApplyConsoleModeOptions(t *testing.T) {
+	const cooked = ENABLE_ECHO_INPUT | ENABLE_LINE_INPUT | ENABLE_PROCESSED_INPUT | ENABLE_INSERT_MODE
+
+	raw := ApplyConsoleModeOptions(cooked, false, WithEcho(false), WithLineInput(false), WithProcessedInput(false))
+	if raw != ENABLE_INSERT_MODE {
+		t.Fatalf("raw mode = %#x, expected %#x", raw, ENABLE_INSERT_MODE)
+	}
+
+	mode := ApplyConsoleModeOptions(cooked, false, WithQuickEdit(false), WithMouse(true))
+	if expected := uint32(cooked | ENABLE_MOUSE_INPUT | ENABLE_EXTENDED_FLAGS); mode != expected {
+		t.Fatalf("mode = %#x, expected %#x", mode, expected)
+	}
+
+	if mode := ApplyConsoleModeOptions(0, false, WithVT(true)); mode != ENABLE_VIRTUAL_TERMINAL_INPUT {
+		t.Fatalf("input VT mode = %#x, expected %#x", mode, ENABLE_VIRTUAL_TERMINAL_INPUT)
+	}
+	if mode := ApplyConsoleModeOptions(ENABLE_PROCESSED_OUTPUT, true, WithVT(true)); mode != ENABLE_PROCESSED_OUTPUT|ENABLE_VIRTUAL_TERMINAL_PROCESSING {
+		t.Fatalf("output VT mode = %#x", mode)
+	}
+}
@@ -0,0 +1,121 @@
+package term
+
+import (
+	"sync"
+	"time"
+)
+
+// resizeDebounce is how long MonitorSize waits for the size to settle, as
+// dragging the corner of a window produces a burst of size changes.
+const resizeDebounce = 100 * time.Millisecond
+
+// MonitorSize calls onResize with the size of the terminal connected to the
+// given file descriptor, once right away and then every time it changes, until
+// the returned function is called. Bursts of changes are coalesced into one
+// call with the final size. Calls are never concurrent, and there are none
+// after stop returns, so stop must not be called from onResize.
+func MonitorSize(fd uintptr, onResize func(Winsize)) (stop func()) {
+	var last Winsize
+	if ws, err := GetWinsize(fd); err == nil {
+		last = *ws
+		onResize(last)
+	}
+
+	sizes, stopEvents := ResizeEvents(fd)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		var settled <-chan time.Time
+		for {
+			select {
+			case <-done:
+				return
+			case _, ok := <-sizes:
+				if !ok {
+					return
+				}
+				settled = time.After(resizeDebounce)
+			case <-settled:
+				settled = nil
+				if ws, err := GetWinsize(fd); err == nil && *ws != last {
+					last = *ws
+					onResize(last)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stopEvents()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// The first resize of MonitorTtySize is attempted up to ttyResizeAttempts
+// times, waiting a little longer after each failure.
+const (
+	ttyResizeAttempts   = 5
+	ttyResizeRetryDelay = 10 * time.Millisecond
+)
+
+// MonitorTtySize keeps the tty of a container or exec process the size of
+// the terminal connected to the given file descriptor, calling resize to
+// change it. The first call, with the size of the terminal when the process
+// starts, is retried a few times if it fails, as the process may not be ready
+// to be resized yet; after that, resize is called as MonitorSize calls
+// onResize, and its errors are ignored. The returned function stops
+// monitoring; it must not be called from resize.
+func MonitorTtySize(fd uintptr, resize func(Winsize) error) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		var sent *Winsize
+		for attempt := 1; attempt <= ttyResizeAttempts; attempt++ {
+			ws, err := GetWinsize(fd)
+			if err != nil {
+				break
+			}
+			if err := resize(*ws); err == nil {
+				sent = ws
+				break
+			}
+			if attempt == ttyResizeAttempts {
+				break
+			}
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Duration(attempt) * ttyResizeRetryDelay):
+			}
+		}
+
+		stopMonitor := MonitorSize(fd, func(ws Winsize) {
+			if sent != nil && ws == *sent {
+				// the size was just sent
+				sent = nil
+				return
+			}
+			sent = nil
+			resize(ws)
+		})
+		<-done
+		stopMonitor()
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// +build !windows
+
+package term
+
+import "syscall"
+
+// SetNonblock puts the given file descriptor, usually stdin, into non-blocking
+// mode for callers running their own select loop over local input and the
+// container streams: reads return EAGAIN instead of waiting for input. The
+// returned function puts the file descriptor back into the mode it was in.
+// As the mode is shared with every process using the same terminal, it must
+// be called before exiting. Note that calling Fd on an *os.File puts its file
+// descriptor back into blocking mode.
+func SetNonblock(fd uintptr) (restore func() error, err error) {
+	flags, errno := getFileFlags(fd)
+	if errno != 0 {
+		return nil, errno
+	}
+	if err := syscall.SetNonblock(int(fd), true); err != nil {
+		return nil, err
+	}
+
+	restore = func() error {
+		return syscall.SetNonblock(int(fd), flags&syscall.O_NONBLOCK != 0)
+	}
+	return restore, nil
+}
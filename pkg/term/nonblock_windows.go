@@ -0,0 +1,48 @@
+// +build windows
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const KEY_EVENT = 0x0001
+
+// inputRecord is an INPUT_RECORD holding a KEY_EVENT_RECORD, the only kind
+// of event looked at here.
+type inputRecord struct {
+	eventType       uint16
+	_               uint16
+	keyDown         int32
+	repeatCount     uint16
+	virtualKeyCode  uint16
+	virtualScanCode uint16
+	unicodeChar     uint16
+	controlKeyState uint32
+}
+
+var peekConsoleInputProc = kernel32DLL.NewProc("PeekConsoleInputW")
+
+// SetNonblock exists for compatibility with other platforms: console handles
+// cannot be made non-blocking, so it returns syscall.EWINDOWS. Use
+// InputPending to poll the console before reading instead.
+func SetNonblock(fd uintptr) (restore func() error, err error) {
+	return nil, syscall.EWINDOWS
+}
+
+// InputPending returns true if the console input buffer connected to the given
+// file descriptor holds a key press, so that a read from it won't block.
+func InputPending(fd uintptr) (bool, error) {
+	var records [16]inputRecord
+	var n uint32
+	if _, err := callProc(peekConsoleInputProc, fd, uintptr(unsafe.Pointer(&records[0])), uintptr(len(records)), uintptr(unsafe.Pointer(&n))); err != nil {
+		return false, err
+	}
+	for _, record := range records[:n] {
+		if record.eventType == KEY_EVENT && record.keyDown != 0 && record.unicodeChar != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
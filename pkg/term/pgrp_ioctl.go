@@ -0,0 +1,18 @@
+// +build !windows,!solaris
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func tcgetpgrp(fd uintptr) (int, syscall.Errno) {
+	var pgrp int32
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGPGRP), uintptr(unsafe.Pointer(&pgrp)))
+	return int(pgrp), err
+}
+
+func getpgrp() int {
+	return syscall.Getpgrp()
+}
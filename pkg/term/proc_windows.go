@@ -0,0 +1,108 @@
+// +build windows
+
+package term
+
+import (
+	"fmt"
+	"sync/atomic"
+	"syscall"
+)
+
+// ProcError is returned when a function of a system DLL is missing, e.g. on
+// Windows versions predating it or on Nano Server, which lacks most of the
+// console API.
+type ProcError struct {
+	Name string
+	Err  error
+}
+
+func (e *ProcError) Error() string {
+	return fmt.Sprintf("%s is not available on this version of Windows: %v", e.Name, e.Err)
+}
+
+// findProc looks up proc, returning a ProcError if it is missing where
+// proc.Call would panic. Found procs are cached by proc itself, so this is
+// only an atomic load once proc has been found.
+func findProc(proc *syscall.LazyProc) error {
+	if err := proc.Find(); err != nil {
+		return &ProcError{Name: proc.Name, Err: err}
+	}
+	return nil
+}
+
+// callProc calls proc, a function returning 0 on failure as most of the
+// console API does, and returns its result. On failure, the error is the last
+// error of the thread, or EINVAL if it wasn't set.
+func callProc(proc *syscall.LazyProc, args ...uintptr) (uintptr, error) {
+	r, errno, err := callProcErrno(proc, args...)
+	if err != nil {
+		return 0, err
+	}
+	if r == 0 {
+		if errno != 0 {
+			return 0, errno
+		}
+		return 0, syscall.EINVAL
+	}
+	return r, nil
+}
+
+// callProcErrno calls proc and returns its result with the last error of the
+// thread, for the functions whose result of 0 isn't always a failure. err is
+// only set if proc is missing.
+func callProcErrno(proc *syscall.LazyProc, args ...uintptr) (r uintptr, errno syscall.Errno, err error) {
+	if err := findProc(proc); err != nil {
+		return 0, 0, err
+	}
+	atomic.AddInt64(&counters.ConsoleCalls, 1)
+	r, _, lastErr := proc.Call(args...)
+	errno, _ = lastErr.(syscall.Errno)
+	return r, errno, nil
+}
+
+// facilityWin32 is the facility of the HRESULTs wrapping a Win32 error code.
+const facilityWin32 = 7
+
+// HRESULTError is a failed HRESULT, as returned by the COM-style functions of
+// the console API such as CreatePseudoConsole.
+type HRESULTError uint32
+
+// Errno returns the Win32 error code wrapped in e, if any.
+func (e HRESULTError) Errno() (syscall.Errno, bool) {
+	if (e>>16)&0x1fff == facilityWin32 {
+		return syscall.Errno(e & 0xffff), true
+	}
+	return 0, false
+}
+
+func (e HRESULTError) Error() string {
+	if errno, ok := e.Errno(); ok {
+		return errno.Error()
+	}
+	return fmt.Sprintf("HRESULT 0x%08X", uint32(e))
+}
+
+// callHRESULTProc calls proc, a function returning an HRESULT, and returns an
+// HRESULTError if it failed.
+func callHRESULTProc(proc *syscall.LazyProc, args ...uintptr) error {
+	if err := findProc(proc); err != nil {
+		return err
+	}
+	atomic.AddInt64(&counters.ConsoleCalls, 1)
+	r, _, _ := proc.Call(args...)
+	if int32(r) < 0 {
+		return HRESULTError(r)
+	}
+	return nil
+}
+
+// callVoidProc calls proc, a function returning nothing, which can only fail
+// by being missing.
+func callVoidProc(proc *syscall.LazyProc, args ...uintptr) error {
+	if err := findProc(proc); err != nil {
+		return err
+	}
+	atomic.AddInt64(&counters.ConsoleCalls, 1)
+	proc.Call(args...)
+	return nil
+}
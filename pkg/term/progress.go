@@ -0,0 +1,19 @@
+package term
+
+import "io"
+
+// WriteProgressLine writes line to out in place of the line the cursor is on,
+// for progress updates: it goes back to the start of the line, writes line
+// truncated to less than width columns, clears what is left of the previous
+// contents and leaves the cursor at the start of the line again. Staying
+// short of the last column keeps the line from wrapping, after which the
+// next update would be written on a new line. A width of 0 means unknown and
+// leaves line untruncated.
+func WriteProgressLine(out io.Writer, line string, width int) error {
+	if width > 0 {
+		line = TruncateToWidth(line, width-1)
+	}
+	// ESC[K = erase to the end of the line
+	_, err := io.WriteString(out, "\r"+line+"\x1b[K\r")
+	return err
+}
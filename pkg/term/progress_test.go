@@ -0,0 +1,27 @@
+package term
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteProgressLine(t *testing.T) {
+	for _, test := range []struct {
+		line     string
+		width    int
+		expected string
+	}{
+		{"Downloading 1 MB/2 MB", 80, "\rDownloading 1 MB/2 MB\x1b[K\r"},
+		{"Downloading 1 MB/2 MB", 12, "\rDownloading\x1b[K\r"},
+		{"ダウンロード", 6, "\rダウ\x1b[K\r"},
+		{"Downloading", 0, "\rDownloading\x1b[K\r"},
+	} {
+		var buf bytes.Buffer
+		if err := WriteProgressLine(&buf, test.line, test.width); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%q, %d: expected %q, got %q", test.line, test.width, test.expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// Package pty allocates pseudo-terminals: a pty pair on Unix, a ConPTY
+// pseudo console on Windows.
+package pty
+
+import "github.com/docker/docker/pkg/term"
+
+// defaultSize is the size given to a pseudo console when none is requested,
+// as one must be given on Windows.
+var defaultSize = term.Winsize{Height: 25, Width: 80}
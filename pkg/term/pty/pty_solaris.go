@@ -0,0 +1,48 @@
+// +build solaris
+
+package pty
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// ErrUnsupported is returned when allocating a pty on Solaris, which the
+// vendored pty library doesn't support.
+var ErrUnsupported = errors.New("Allocating a pty is not supported on Solaris")
+
+// Pty is a pty pair.
+type Pty struct {
+	// Master is the controlling end: what is written to it is input for the
+	// programs on the terminal, and their output is read from it.
+	Master *os.File
+	// Slave is the terminal end, used as stdio by the programs.
+	Slave *os.File
+}
+
+// Open returns ErrUnsupported.
+func Open(ws *term.Winsize) (*Pty, error) {
+	return nil, ErrUnsupported
+}
+
+// Start returns ErrUnsupported.
+func Start(cmd *exec.Cmd, ws *term.Winsize) (*Pty, error) {
+	return nil, ErrUnsupported
+}
+
+// Resize changes the size of the terminal, which sends SIGWINCH to its
+// foreground process group.
+func (p *Pty) Resize(ws *term.Winsize) error {
+	return term.SetWinsize(p.Master.Fd(), ws)
+}
+
+// Close closes both ends of the pair.
+func (p *Pty) Close() error {
+	if p.Slave != nil {
+		p.Slave.Close()
+	}
+	return p.Master.Close()
+}
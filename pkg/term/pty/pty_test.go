@@ -0,0 +1,80 @@
+// +build !windows,!solaris
+
+package pty
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+func TestOpenWithSize(t *testing.T) {
+	p, err := Open(&term.Winsize{Height: 40, Width: 132})
+	if err != nil {
+		t.Skipf("Cannot allocate a pty: %s", err)
+	}
+	defer p.Close()
+
+	if !term.IsTerminal(p.Slave.Fd()) {
+		t.Fatal("the slave is not a terminal")
+	}
+	ws, err := term.GetWinsize(p.Slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Height != 40 || ws.Width != 132 {
+		t.Fatalf("size is %dx%d, expected 132x40", ws.Width, ws.Height)
+	}
+}
+
+func TestStart(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "stty size; tty -s && echo tty")
+	p, err := Start(cmd, &term.Winsize{Height: 24, Width: 100})
+	if err != nil {
+		t.Skipf("Cannot start a command on a pty: %s", err)
+	}
+	defer p.Close()
+
+	reader := bufio.NewReader(p.Master)
+	for _, expected := range []string{"24 100", "tty"} {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line = strings.TrimRight(line, "\r\n"); line != expected {
+			t.Fatalf("got %q, expected %q", line, expected)
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartWithStdio(t *testing.T) {
+	// none of the stdio streams is the pty, which must still become the
+	// controlling terminal
+	cmd := exec.Command("sh", "-c", "echo tty > /dev/tty")
+	cmd.Stdin = strings.NewReader("")
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+	p, err := Start(cmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	line, err := bufio.NewReader(p.Master).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line = strings.TrimRight(line, "\r\n"); line != "tty" {
+		t.Fatalf("got %q, expected %q", line, "tty")
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
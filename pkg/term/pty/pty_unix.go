@@ -0,0 +1,101 @@
+// +build !windows,!solaris
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/docker/docker/pkg/term"
+	krpty "github.com/kr/pty"
+)
+
+// Pty is a pty pair.
+type Pty struct {
+	// Master is the controlling end: what is written to it is input for the
+	// programs on the terminal, and their output is read from it.
+	Master *os.File
+	// Slave is the terminal end, used as stdio by the programs.
+	Slave *os.File
+}
+
+// Open allocates a pty pair of size ws, or of the system default size if ws
+// is nil.
+func Open(ws *term.Winsize) (*Pty, error) {
+	master, slave, err := krpty.Open()
+	if err != nil {
+		return nil, err
+	}
+	p := &Pty{Master: master, Slave: slave}
+	if ws != nil {
+		if err := p.Resize(ws); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Start allocates a pty pair of size ws (or of the default size if ws is nil)
+// and starts cmd in a new session with the pty as controlling terminal and as
+// the stdio streams it doesn't already have; if it has all of them, the slave
+// is passed as an extra file. The slave is closed in the calling process once
+// cmd has started.
+func Start(cmd *exec.Cmd, ws *term.Winsize) (*Pty, error) {
+	p, err := Open(ws)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Stdin == nil {
+		cmd.Stdin = p.Slave
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = p.Slave
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = p.Slave
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = slaveFd(cmd, p.Slave)
+	if err := cmd.Start(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.Slave.Close()
+	p.Slave = nil
+	return p, nil
+}
+
+// slaveFd returns the file descriptor of slave in the process started by cmd,
+// adding it to the extra files if it isn't one of the stdio streams.
+func slaveFd(cmd *exec.Cmd, slave *os.File) int {
+	switch slave {
+	case cmd.Stdin:
+		return 0
+	case cmd.Stdout:
+		return 1
+	case cmd.Stderr:
+		return 2
+	}
+	cmd.ExtraFiles = append(cmd.ExtraFiles, slave)
+	return 3 + len(cmd.ExtraFiles) - 1
+}
+
+// Resize changes the size of the terminal, which sends SIGWINCH to its
+// foreground process group.
+func (p *Pty) Resize(ws *term.Winsize) error {
+	return term.SetWinsize(p.Master.Fd(), ws)
+}
+
+// Close closes both ends of the pair.
+func (p *Pty) Close() error {
+	if p.Slave != nil {
+		p.Slave.Close()
+	}
+	return p.Master.Close()
+}
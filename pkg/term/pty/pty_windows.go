@@ -0,0 +1,35 @@
+// +build windows
+
+package pty
+
+import "github.com/docker/docker/pkg/term"
+
+// Pty is a ConPTY pseudo console. Processes are attached to it by passing
+// Master.Handle() as PROC_THREAD_ATTRIBUTE_PSEUDOCONSOLE when creating them.
+type Pty struct {
+	// Master is the controlling end: what is written to it is input for the
+	// programs on the pseudo console, and their output is read from it.
+	Master *term.PseudoConsole
+}
+
+// Open creates a pseudo console of size ws, or of 80x25 if ws is nil.
+func Open(ws *term.Winsize) (*Pty, error) {
+	if ws == nil {
+		ws = &defaultSize
+	}
+	console, err := term.NewPseudoConsole(ws)
+	if err != nil {
+		return nil, err
+	}
+	return &Pty{Master: console}, nil
+}
+
+// Resize changes the size of the pseudo console.
+func (p *Pty) Resize(ws *term.Winsize) error {
+	return p.Master.Resize(ws)
+}
+
+// Close closes the pseudo console.
+func (p *Pty) Close() error {
+	return p.Master.Close()
+}
@@ -0,0 +1,32 @@
+package term
+
+import "io"
+
+// RedirectPolicy chooses what NewRedirectWriter does with output that doesn't
+// go to a terminal.
+type RedirectPolicy int
+
+const (
+	// RedirectPassthrough writes the output verbatim.
+	RedirectPassthrough RedirectPolicy = iota
+	// RedirectStrip removes escape sequences and control characters, as a
+	// StripWriter does.
+	RedirectStrip
+)
+
+// NewRedirectWriter returns w unchanged if it is a terminal. Otherwise w is a
+// file or a pipe, e.g. with `docker logs > out.txt`, and the output written to
+// it is handled according to policy. Writers without a file descriptor are
+// never terminals. In both cases the output is written to w directly, without
+// any console call.
+func NewRedirectWriter(w io.Writer, policy RedirectPolicy) io.Writer {
+	if f, ok := w.(interface {
+		Fd() uintptr
+	}); ok && IsTerminal(f.Fd()) {
+		return w
+	}
+	if policy == RedirectStrip {
+		return NewStripWriter(w)
+	}
+	return w
+}
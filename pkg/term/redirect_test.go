@@ -0,0 +1,37 @@
+package term
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestRedirectWriterPassthrough(t *testing.T) {
+	var buf bytes.Buffer
+	if w := NewRedirectWriter(&buf, RedirectPassthrough); w != &buf {
+		t.Fatalf("Expected the writer to be returned unchanged, got %T", w)
+	}
+}
+
+func TestRedirectWriterStripFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "redirect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	w := NewRedirectWriter(f, RedirectStrip)
+	if _, ok := w.(*StripWriter); !ok {
+		t.Fatalf("Expected a StripWriter for a file, got %T", w)
+	}
+	w.Write([]byte("\x1b[1;31mred\x1b[0m\r\n"))
+	out, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "red\r\n" {
+		t.Fatalf("Expected %q, got %q", "red\r\n", out)
+	}
+}
@@ -0,0 +1,91 @@
+package term
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ProgressRegion keeps status lines, such as one per layer during docker pull,
+// pinned at the bottom of a terminal and updates each in place, while other
+// output printed with Print scrolls above them.
+type ProgressRegion struct {
+	out io.Writer
+	fd  uintptr
+	// lines are the current status lines, in the order they appeared on
+	// screen; the cursor is at the start of the line below the last one.
+	lines []string
+	ids   map[string]int
+}
+
+// NewProgressRegion returns a ProgressRegion writing to out, a terminal whose
+// width is that of the terminal fd.
+func NewProgressRegion(out io.Writer, fd uintptr) *ProgressRegion {
+	return &ProgressRegion{out: out, fd: fd, ids: make(map[string]int)}
+}
+
+func (r *ProgressRegion) width() int {
+	if ws, err := GetWinsize(r.fd); err == nil {
+		return int(ws.Width)
+	}
+	return 0
+}
+
+// Update sets the status line for id to line, adding it at the bottom of the
+// region if there was none.
+func (r *ProgressRegion) Update(id, line string) error {
+	i, ok := r.ids[id]
+	if !ok {
+		r.ids[id] = len(r.lines)
+		r.lines = append(r.lines, line)
+		if err := WriteProgressLine(r.out, line, r.width()); err != nil {
+			return err
+		}
+		_, err := io.WriteString(r.out, "\n")
+		return err
+	}
+
+	r.lines[i] = line
+	up := len(r.lines) - i
+	// ESC[nA = move cursor up n rows, ESC[nB = move cursor down n rows
+	if _, err := fmt.Fprintf(r.out, "\x1b[%dA", up); err != nil {
+		return err
+	}
+	if err := WriteProgressLine(r.out, line, r.width()); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(r.out, "\x1b[%dB", up)
+	return err
+}
+
+// Print writes text above the status lines, which are redrawn below it.
+func (r *ProgressRegion) Print(text string) error {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if len(r.lines) == 0 {
+		_, err := io.WriteString(r.out, text)
+		return err
+	}
+	// ESC[J = erase to the end of the screen
+	if _, err := fmt.Fprintf(r.out, "\x1b[%dA\r\x1b[J%s", len(r.lines), text); err != nil {
+		return err
+	}
+	width := r.width()
+	for _, line := range r.lines {
+		if err := WriteProgressLine(r.out, line, width); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(r.out, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset forgets the status lines, leaving them on screen as they are:
+// following output goes below them and new status lines start a new region.
+func (r *ProgressRegion) Reset() {
+	r.lines = nil
+	r.ids = make(map[string]int)
+}
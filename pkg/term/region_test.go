@@ -0,0 +1,32 @@
+package term
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgressRegion(t *testing.T) {
+	var buf bytes.Buffer
+	// an invalid fd, the width is unknown
+	r := NewProgressRegion(&buf, ^uintptr(0))
+
+	step := func(f func() error, expected string) {
+		buf.Reset()
+		if err := f(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Fatalf("expected %q, got %q", expected, buf.String())
+		}
+	}
+	step(func() error { return r.Update("a", "a: 1%") }, "\ra: 1%\x1b[K\r\n")
+	step(func() error { return r.Update("b", "b: 1%") }, "\rb: 1%\x1b[K\r\n")
+	step(func() error { return r.Update("a", "a: 50%") }, "\x1b[2A\ra: 50%\x1b[K\r\x1b[2B")
+	step(func() error { return r.Update("b", "b: 20%") }, "\x1b[1A\rb: 20%\x1b[K\r\x1b[1B")
+	step(func() error { return r.Print("hello") },
+		"\x1b[2A\r\x1b[Jhello\n\ra: 50%\x1b[K\r\n\rb: 20%\x1b[K\r\n")
+
+	r.Reset()
+	step(func() error { return r.Print("done\n") }, "done\n")
+	step(func() error { return r.Update("a", "a: 1%") }, "\ra: 1%\x1b[K\r\n")
+}
@@ -0,0 +1,43 @@
+// +build !windows
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ResizeEvents sends the size of the terminal connected to the given file
+// descriptor on the returned channel every time it changes, until the
+// returned function is called, which also closes the channel. If the receiver
+// falls behind, only the latest size is kept.
+func ResizeEvents(fd uintptr) (<-chan Winsize, func()) {
+	sizes := make(chan Winsize, 1)
+	sigchan := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigchan, syscall.SIGWINCH)
+
+	go func() {
+		defer close(sizes)
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigchan:
+			}
+			if ws, err := GetWinsize(fd); err == nil {
+				sendLatestWinsize(sizes, *ws)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return sizes, func() {
+		once.Do(func() {
+			signal.Stop(sigchan)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// +build windows
+
+package term
+
+import (
+	"sync"
+	"time"
+)
+
+// resizePollInterval is how often the console window size is checked. The
+// console only reports size changes as input events, which would be consumed
+// by whoever reads the input.
+const resizePollInterval = 250 * time.Millisecond
+
+// ResizeEvents sends the size of the console connected to the given file
+// descriptor on the returned channel every time it changes, until the
+// returned function is called, which also closes the channel. If the receiver
+// falls behind, only the latest size is kept.
+func ResizeEvents(fd uintptr) (<-chan Winsize, func()) {
+	sizes := make(chan Winsize, 1)
+	ticker := time.NewTicker(resizePollInterval)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(sizes)
+		var last Winsize
+		if ws, err := GetWinsize(fd); err == nil {
+			last = *ws
+		}
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if ws, err := GetWinsize(fd); err == nil && *ws != last {
+				last = *ws
+				sendLatestWinsize(sizes, last)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return sizes, func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
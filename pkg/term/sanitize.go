@@ -0,0 +1,175 @@
+package term
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+// SanitizePolicy lists the escape sequences a SanitizingWriter lets through
+// besides the ones always allowed: colors and other text attributes, cursor
+// movement and erasing. The zero value blocks all of them.
+type SanitizePolicy struct {
+	// Title allows changing the window and icon titles (OSC 0, 1 and 2).
+	Title bool
+	// Clipboard allows setting and reading the clipboard (OSC 52).
+	Clipboard bool
+	// Hyperlinks allows hyperlinks (OSC 8).
+	Hyperlinks bool
+	// Queries allows the sequences the terminal answers as if the answer
+	// was typed, such as device attributes and status reports, and color
+	// queries. The answers end up as input of whatever reads the terminal.
+	Queries bool
+	// WindowOps allows moving, resizing and reporting on the window (CSI t).
+	WindowOps bool
+	// ControlStrings allows the other control strings: OSC not listed
+	// above, DCS, APC, PM and SOS.
+	ControlStrings bool
+}
+
+// NewSanitizingWriter returns a writer that drops the escape sequences that
+// untrusted output could use to attack the terminal displaying it, unless
+// allowed by policy, e.g. to show the logs of a container without letting it
+// change the window title or fill the clipboard. ENQ, which makes some
+// terminals send an answerback message, is treated as a query. C1 controls,
+// raw or encoded in UTF-8, are treated as the equivalent ESC sequences and
+// written as such if allowed.
+func NewSanitizingWriter(w io.Writer, policy SanitizePolicy) *SanitizingWriter {
+	s := &SanitizingWriter{w: w, policy: policy}
+	s.seq = s.scratch[:0]
+	return s
+}
+
+// SanitizingWriter is the writer returned by NewSanitizingWriter.
+type SanitizingWriter struct {
+	w      io.Writer
+	policy SanitizePolicy
+	escapeParser
+	// out is reused across writes for the sanitized output.
+	out bytes.Buffer
+	// pending holds an incomplete UTF-8 character ending the last write,
+	// which may turn out to be a C1 control.
+	pending []byte
+}
+
+func (s *SanitizingWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	if len(s.pending) > 0 {
+		p = append(s.pending, p...)
+		s.pending = nil
+	}
+
+	out := &s.out
+	out.Reset()
+	for i := 0; i < len(p); {
+		if !utf8.FullRune(p[i:]) {
+			s.pending = append([]byte(nil), p[i:]...)
+			break
+		}
+		// C1 controls are handled as the equivalent ESC sequences,
+		// which terminals treat them as
+		if final, size := c1Control(p[i:]); size > 0 {
+			s.feed(out, '\x1b')
+			s.feed(out, final)
+			i += size
+			continue
+		}
+
+		size := 1
+		if p[i] >= utf8.RuneSelf {
+			_, size = utf8.DecodeRune(p[i:])
+		}
+		for _, b := range p[i : i+size] {
+			switch {
+			case !s.idle() || b == '\x1b':
+				s.feed(out, b)
+			case b != '\x05' || s.policy.Queries:
+				out.WriteByte(b)
+			}
+		}
+		i += size
+	}
+
+	_, err := s.w.Write(out.Bytes())
+	if out.Cap() > maxRetainedOutput {
+		s.out = bytes.Buffer{}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// feed passes b to the escape parser, writing the sequence it completes to out
+// if the policy allows it.
+func (s *SanitizingWriter) feed(out *bytes.Buffer, b byte) {
+	if seq := s.next(b); seq != nil && s.policy.allows(seq) {
+		out.Write(seq)
+	}
+}
+
+// c1Control returns the byte following ESC in the 7-bit equivalent of the C1
+// control (0x80 to 0x9f) p starts with, e.g. '[' for CSI, and its length in p:
+// 2 if encoded in UTF-8, 1 if it is a raw byte, which isn't valid UTF-8. size
+// is 0 if p doesn't start with a C1 control.
+func c1Control(p []byte) (final byte, size int) {
+	switch {
+	case p[0] >= 0x80 && p[0] <= 0x9f:
+		return p[0] - 0x40, 1
+	case p[0] == 0xc2 && len(p) > 1 && p[1] >= 0x80 && p[1] <= 0x9f:
+		return p[1] - 0x40, 2
+	}
+	return 0, 0
+}
+
+// allows returns true if the complete escape sequence seq is allowed.
+func (policy SanitizePolicy) allows(seq []byte) bool {
+	switch kind := seq[1]; {
+	case kind == ']':
+		return policy.allowsOSC(seq[2:])
+	case isControlString(kind):
+		return policy.ControlStrings
+	case kind == '[':
+		params, final := seq[2:len(seq)-1], seq[len(seq)-1]
+		switch {
+		case final == 't':
+			return policy.WindowOps
+		case final == 'c' || final == 'n' || final == 'x':
+			// device attributes, device status and terminal
+			// parameters reports
+			return policy.Queries
+		case final == 'p' && bytes.IndexByte(params, '$') >= 0:
+			// mode report (DECRQM)
+			return policy.Queries
+		case final == 'q' && bytes.HasPrefix(params, []byte(">")):
+			// terminal name and version (XTVERSION)
+			return policy.Queries
+		}
+	case kind == 'Z':
+		// identify terminal (DECID)
+		return policy.Queries
+	}
+	return true
+}
+
+// allowsOSC returns true if the OSC sequence with the given contents, from
+// after ESC ] to the terminator, is allowed.
+func (policy SanitizePolicy) allowsOSC(osc []byte) bool {
+	ps := osc
+	if i := bytes.IndexByte(osc, ';'); i >= 0 {
+		ps = osc[:i]
+	}
+	if bytes.Contains(osc, []byte(";?")) && !policy.Queries {
+		// e.g. OSC 11 ; ? asks for the background color
+		return false
+	}
+	switch string(ps) {
+	case "0", "1", "2":
+		return policy.Title
+	case "52":
+		return policy.Clipboard
+	case "8":
+		return policy.Hyperlinks
+	}
+	return policy.ControlStrings
+}
@@ -0,0 +1,72 @@
+package term
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSanitizingWriter(t *testing.T) {
+	for _, test := range []struct {
+		policy   SanitizePolicy
+		input    string
+		expected string
+	}{
+		{SanitizePolicy{}, "\x1b[1;31mred\x1b[0m\x1b[2J\x1b[H\x1b[3A\x1b[K", "\x1b[1;31mred\x1b[0m\x1b[2J\x1b[H\x1b[3A\x1b[K"},
+		{SanitizePolicy{}, "a\x1b]0;pwned\x07b\x1b]2;pwned\x1b\\c", "abc"},
+		{SanitizePolicy{Title: true}, "\x1b]0;title\x07", "\x1b]0;title\x07"},
+		{SanitizePolicy{}, "\x1b]52;c;cm0gLXJmIH4K\x07", ""},
+		{SanitizePolicy{Clipboard: true}, "\x1b]52;c;?\x07", ""},
+		{SanitizePolicy{Clipboard: true, Queries: true}, "\x1b]52;c;?\x07", "\x1b]52;c;?\x07"},
+		{SanitizePolicy{}, "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{SanitizePolicy{Hyperlinks: true}, "\x1b]8;;http://x\x07", "\x1b]8;;http://x\x07"},
+		{SanitizePolicy{}, "\x1b[c\x1b[>0c\x1b[6n\x1b[5n\x1b[?2004$p\x1bZ\x05", ""},
+		{SanitizePolicy{Queries: true}, "\x1b[6n\x05", "\x1b[6n\x05"},
+		{SanitizePolicy{}, "\x1b[8;100;200t\x1b[21t", ""},
+		{SanitizePolicy{}, "\x1bP$q\"p\x1b\\\x1b_apc\x1b\\\x1b]11;?\x07\x1b]4;1;rgb:ff/00/00\x07", ""},
+		{SanitizePolicy{ControlStrings: true}, "\x1b]4;1;rgb:ff/00/00\x07", "\x1b]4;1;rgb:ff/00/00\x07"},
+		{SanitizePolicy{ControlStrings: true}, "\x1b]11;?\x07", ""},
+		{SanitizePolicy{}, "\x1b[?1049h\x1b[?25l\x1b(B\x1b7\x1b8", "\x1b[?1049h\x1b[?25l\x1b(B\x1b7\x1b8"},
+		{SanitizePolicy{}, "\x1b[>q\x1b[>0q", ""},
+		{SanitizePolicy{Queries: true}, "\x1b[>q", "\x1b[>q"},
+		// C1 controls, encoded in UTF-8 and raw
+		{SanitizePolicy{}, "a\xc2\x9d52;c;cm0gLXJmIH4K\x07b\xc2\x9d0;pwned\xc2\x9cc", "abc"},
+		{SanitizePolicy{}, "a\x9d2;pwned\x9cb\x90$q\"p\x9cc\x9b6n", "abc"},
+		{SanitizePolicy{}, "\xc2\x9b31mred\x9b0m", "\x1b[31mred\x1b[0m"},
+		{SanitizePolicy{Title: true}, "\xc2\x9d0;t\xc3\xa9\xc2\x9c", "\x1b]0;t\xc3\xa9\x1b\\"},
+		// UTF-8 characters whose continuation bytes are in the C1 range
+		{SanitizePolicy{}, "\xe2\x82\xac \xc2\xa0\xc3\x89", "\xe2\x82\xac \xc2\xa0\xc3\x89"},
+	} {
+		var buf bytes.Buffer
+		w := NewSanitizingWriter(&buf, test.policy)
+		if n, err := w.Write([]byte(test.input)); err != nil || n != len(test.input) {
+			t.Fatalf("%q: Write returned %d, %v", test.input, n, err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%+v %q: expected %q, got %q", test.policy, test.input, test.expected, buf.String())
+		}
+	}
+}
+
+func TestSanitizingWriterSplitSequence(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSanitizingWriter(&buf, SanitizePolicy{})
+	for _, s := range []string{"a\x1b]", "0;ti", "tle\x07b\x1b[3", "2mc"} {
+		w.Write([]byte(s))
+	}
+	if buf.String() != "ab\x1b[32mc" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+func TestSanitizingWriterSplitC1(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSanitizingWriter(&buf, SanitizePolicy{})
+	for _, s := range []string{"a\xc2", "\x9d0;title\x07b\xe2\x82", "\xac"} {
+		if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+			t.Fatalf("%q: Write returned %d, %v", s, n, err)
+		}
+	}
+	if buf.String() != "ab\xe2\x82\xac" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,127 @@
+// +build windows
+
+package term
+
+import (
+	"time"
+	"unsafe"
+)
+
+const (
+	// Must be set along with ENABLE_QUICK_EDIT_MODE or ENABLE_INSERT_MODE for
+	// SetConsoleMode to change them.
+	ENABLE_EXTENDED_FLAGS = 0x0080
+
+	// Flags of CONSOLE_SELECTION_INFO
+	// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms682122(v=vs.85).aspx
+	CONSOLE_NO_SELECTION          = 0x0000
+	CONSOLE_SELECTION_IN_PROGRESS = 0x0001
+	CONSOLE_SELECTION_NOT_EMPTY   = 0x0002
+	CONSOLE_MOUSE_SELECTION       = 0x0004
+	CONSOLE_MOUSE_DOWN            = 0x0008
+)
+
+var getConsoleSelectionInfoProc = kernel32DLL.NewProc("GetConsoleSelectionInfo")
+
+type CONSOLE_SELECTION_INFO struct {
+	dwFlags           uint32
+	dwSelectionAnchor COORD
+	srSelection       SMALL_RECT
+}
+
+func GetConsoleSelectionInfo() (*CONSOLE_SELECTION_INFO, error) {
+	var info CONSOLE_SELECTION_INFO
+	if _, err := callProc(getConsoleSelectionInfoProc, uintptr(unsafe.Pointer(&info))); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Selection describes the text selection of a console window.
+type Selection struct {
+	// InProgress is set from the moment the user starts selecting until
+	// the selection is copied or cancelled.
+	InProgress bool
+	// NotEmpty is set once some text has been selected.
+	NotEmpty bool
+	// Mouse is set when the selection was started with the mouse (QuickEdit)
+	// rather than from the Mark menu entry.
+	Mouse bool
+	// MouseDown is set while the mouse button is held.
+	MouseDown bool
+	// Anchor is where the selection started, in buffer coordinates.
+	Anchor COORD
+	// Rect is the selected rectangle, in buffer coordinates.
+	Rect SMALL_RECT
+}
+
+// IsMarkMode returns true if the selection is being made with the keyboard
+// after choosing Mark from the console menu. In mark mode, key presses move
+// the selection instead of reaching the application.
+func (s *Selection) IsMarkMode() bool {
+	return s.InProgress && !s.Mouse
+}
+
+// GetSelection returns the current selection of the console.
+func GetSelection() (*Selection, error) {
+	info, err := GetConsoleSelectionInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &Selection{
+		InProgress: info.dwFlags&CONSOLE_SELECTION_IN_PROGRESS != 0,
+		NotEmpty:   info.dwFlags&CONSOLE_SELECTION_NOT_EMPTY != 0,
+		Mouse:      info.dwFlags&CONSOLE_MOUSE_SELECTION != 0,
+		MouseDown:  info.dwFlags&CONSOLE_MOUSE_DOWN != 0,
+		Anchor:     info.dwSelectionAnchor,
+		Rect:       info.srSelection,
+	}, nil
+}
+
+// IsOutputPaused returns true while the user is selecting text in the
+// console. The console stops processing output until the selection ends, so
+// writes block and interactive sessions appear hung.
+func IsOutputPaused() bool {
+	s, err := GetSelection()
+	if err != nil {
+		return false
+	}
+	return s.InProgress
+}
+
+// NotifyOutputPaused polls the console selection state every interval and
+// sends on the returned channel whenever output gets paused (true) or resumed
+// (false), until stop is closed.
+func NotifyOutputPaused(interval time.Duration, stop <-chan struct{}) <-chan bool {
+	c := make(chan bool, 1)
+	go func() {
+		defer close(c)
+		paused := false
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			if p := IsOutputPaused(); p != paused {
+				paused = p
+				select {
+				case c <- paused:
+				case <-stop:
+					return
+				}
+			}
+		}
+	}()
+	return c
+}
+
+// DisableQuickEdit turns off QuickEdit mode on the given console input handle
+// so that mouse clicks don't start a selection and pause the output, e.g.
+// while a raw TTY session is active. The previous mode is restored by calling
+// RestoreTerminal with the returned state.
+func DisableQuickEdit(fd uintptr) (*State, error) {
+	return SetConsoleModeOptions(fd, WithQuickEdit(false))
+}
@@ -0,0 +1,113 @@
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Character attributes of a console cell
+// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms682088(v=vs.85).aspx#_win32_character_attributes
+const (
+	FOREGROUND_BLUE      = 0x0001
+	FOREGROUND_GREEN     = 0x0002
+	FOREGROUND_RED       = 0x0004
+	FOREGROUND_INTENSITY = 0x0008
+	BACKGROUND_BLUE      = 0x0010
+	BACKGROUND_GREEN     = 0x0020
+	BACKGROUND_RED       = 0x0040
+	BACKGROUND_INTENSITY = 0x0080
+
+	COMMON_LVB_LEADING_BYTE  = 0x0100
+	COMMON_LVB_TRAILING_BYTE = 0x0200
+	COMMON_LVB_REVERSE_VIDEO = 0x4000
+	COMMON_LVB_UNDERSCORE    = 0x8000
+)
+
+// Cell is a character cell of a console screen buffer.
+type Cell struct {
+	Char       rune
+	Attributes uint16
+}
+
+// Snapshot is a copy of the visible part of a console screen buffer.
+type Snapshot struct {
+	Width  int
+	Height int
+	// Cells holds Height rows of Width cells.
+	Cells []Cell
+	// CursorX and CursorY are the cursor position relative to the window.
+	CursorX int
+	CursorY int
+}
+
+// Row returns the cells of row y.
+func (s *Snapshot) Row(y int) []Cell {
+	return s.Cells[y*s.Width : (y+1)*s.Width]
+}
+
+// Text returns the characters of the snapshot, one line per row, without
+// trailing spaces.
+func (s *Snapshot) Text() string {
+	var buf bytes.Buffer
+	for y := 0; y < s.Height; y++ {
+		var line bytes.Buffer
+		for _, c := range s.Row(y) {
+			if c.Attributes&COMMON_LVB_TRAILING_BYTE != 0 {
+				// second half of a double-width character
+				continue
+			}
+			line.WriteRune(c.Char)
+		}
+		buf.WriteString(strings.TrimRight(line.String(), " "))
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// ANSI returns the snapshot as text with SGR sequences reproducing the colors
+// of the cells, one line per row.
+func (s *Snapshot) ANSI() string {
+	var buf bytes.Buffer
+	for y := 0; y < s.Height; y++ {
+		attributes := -1
+		for _, c := range s.Row(y) {
+			if c.Attributes&COMMON_LVB_TRAILING_BYTE != 0 {
+				continue
+			}
+			if a := int(c.Attributes &^ (COMMON_LVB_LEADING_BYTE | COMMON_LVB_TRAILING_BYTE)); a != attributes {
+				buf.WriteString(attributesToSGR(uint16(a)))
+				attributes = a
+			}
+			buf.WriteRune(c.Char)
+		}
+		buf.WriteString("\x1b[0m\n")
+	}
+	return buf.String()
+}
+
+// attributesToSGR returns the SGR sequence selecting the given attributes.
+func attributesToSGR(a uint16) string {
+	fg := 30 + consoleColorToANSI(a)
+	if a&FOREGROUND_INTENSITY != 0 {
+		fg += 60
+	}
+	bg := 40 + consoleColorToANSI(a>>4)
+	if a&BACKGROUND_INTENSITY != 0 {
+		bg += 60
+	}
+	sgr := fmt.Sprintf("\x1b[0;%d;%d", fg, bg)
+	if a&COMMON_LVB_UNDERSCORE != 0 {
+		sgr += ";4"
+	}
+	if a&COMMON_LVB_REVERSE_VIDEO != 0 {
+		sgr += ";7"
+	}
+	return sgr + "m"
+}
+
+// consoleColorToANSI converts the low 3 bits of a console color (blue, green,
+// red) to an ANSI color index (red, green, blue).
+func consoleColorToANSI(c uint16) int {
+	return int((c&FOREGROUND_BLUE)<<2 | c&FOREGROUND_GREEN | (c&FOREGROUND_RED)>>2)
+}
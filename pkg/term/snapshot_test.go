@@ -0,0 +1,65 @@
+package term
+
+import "testing"
+
+func newTestSnapshot(lines []string, attributes uint16) *Snapshot {
+	s := &Snapshot{Height: len(lines)}
+	for _, l := range lines {
+		if len(l) > s.Width {
+			s.Width = len(l)
+		}
+	}
+	for _, l := range lines {
+		for x := 0; x < s.Width; x++ {
+			c := Cell{Char: ' ', Attributes: attributes}
+			if x < len(l) {
+				c.Char = rune(l[x])
+			}
+			s.Cells = append(s.Cells, c)
+		}
+	}
+	return s
+}
+
+func TestSnapshotText(t *testing.T) {
+	s := newTestSnapshot([]string{"hello  ", "", "  world"}, FOREGROUND_RED|FOREGROUND_GREEN|FOREGROUND_BLUE)
+	if text, expected := s.Text(), "hello\n\n  world\n"; text != expected {
+		t.Fatalf("Text() = %q, expected %q", text, expected)
+	}
+}
+
+func TestSnapshotTextSkipsTrailingHalves(t *testing.T) {
+	s := &Snapshot{Width: 3, Height: 1, Cells: []Cell{
+		{Char: '日', Attributes: COMMON_LVB_LEADING_BYTE},
+		{Char: '日', Attributes: COMMON_LVB_TRAILING_BYTE},
+		{Char: 'a'},
+	}}
+	if text, expected := s.Text(), "日a\n"; text != expected {
+		t.Fatalf("Text() = %q, expected %q", text, expected)
+	}
+}
+
+func TestSnapshotANSI(t *testing.T) {
+	s := &Snapshot{Width: 3, Height: 1, Cells: []Cell{
+		{Char: 'a', Attributes: FOREGROUND_RED},
+		{Char: 'b', Attributes: FOREGROUND_RED},
+		{Char: 'c', Attributes: FOREGROUND_BLUE | FOREGROUND_INTENSITY | BACKGROUND_GREEN | COMMON_LVB_UNDERSCORE},
+	}}
+	expected := "\x1b[0;31;40mab\x1b[0;94;42;4mc\x1b[0m\n"
+	if ansi := s.ANSI(); ansi != expected {
+		t.Fatalf("ANSI() = %q, expected %q", ansi, expected)
+	}
+}
+
+// BenchmarkSnapshotANSI renders an 80x25 window with a color change every 10
+// cells.
+func BenchmarkSnapshotANSI(b *testing.B) {
+	s := &Snapshot{Width: 80, Height: 25}
+	for i := 0; i < s.Width*s.Height; i++ {
+		s.Cells = append(s.Cells, Cell{Char: 'x', Attributes: uint16(i/10) & 0xff})
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.ANSI()
+	}
+}
@@ -0,0 +1,62 @@
+// +build windows
+
+package term
+
+import "unsafe"
+
+// maxReadCells bounds the cells read by one ReadConsoleOutputW call, which
+// fails when its buffer exceeds the console's 64KB heap.
+const maxReadCells = 8192
+
+var readConsoleOutputProc = kernel32DLL.NewProc("ReadConsoleOutputW")
+
+type CHAR_INFO struct {
+	UnicodeChar uint16
+	Attributes  WORD
+}
+
+func ReadConsoleOutput(fileDesc uintptr, buffer []CHAR_INFO, bufferSize COORD, bufferCoord COORD, readRegion *SMALL_RECT) error {
+	if _, err := callProc(readConsoleOutputProc, fileDesc, uintptr(unsafe.Pointer(&buffer[0])), coordToUintptr(bufferSize), coordToUintptr(bufferCoord), uintptr(unsafe.Pointer(readRegion))); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CaptureWindow returns a snapshot of the characters and attributes visible
+// in the console window of the given screen buffer.
+func CaptureWindow(fd uintptr) (*Snapshot, error) {
+	info, err := GetConsoleScreenBufferInfo(fd)
+	if err != nil {
+		return nil, err
+	}
+	window := info.srWindow
+	size := WindowSize(window)
+	cursor := BufferToWindow(window, info.dwCursorPosition)
+	s := &Snapshot{
+		Width:   int(size.X),
+		Height:  int(size.Y),
+		CursorX: int(cursor.X),
+		CursorY: int(cursor.Y),
+	}
+	s.Cells = make([]Cell, 0, s.Width*s.Height)
+
+	rows := maxReadCells / s.Width
+	if rows == 0 {
+		rows = 1
+	}
+	buffer := make([]CHAR_INFO, rows*s.Width)
+	for top := window.Top; top <= window.Bottom; top += SHORT(rows) {
+		region := SMALL_RECT{Left: window.Left, Top: top, Right: window.Right, Bottom: top + SHORT(rows) - 1}
+		if region.Bottom > window.Bottom {
+			region.Bottom = window.Bottom
+		}
+		bufferSize := COORD{X: SHORT(s.Width), Y: region.Bottom - region.Top + 1}
+		if err := ReadConsoleOutput(fd, buffer, bufferSize, COORD{}, &region); err != nil {
+			return nil, err
+		}
+		for _, c := range buffer[:int(bufferSize.X)*int(bufferSize.Y)] {
+			s.Cells = append(s.Cells, Cell{Char: rune(c.UnicodeChar), Attributes: uint16(c.Attributes)})
+		}
+	}
+	return s, nil
+}
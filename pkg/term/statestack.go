@@ -0,0 +1,73 @@
+package term
+
+import "sync"
+
+// StateStack nests mode changes on the terminal connected to one file
+// descriptor, such as a password prompt inside an attach session: each change
+// is undone by its own function, in any order, and the terminal is only put
+// back into the state a change found once all the changes made after it have
+// been undone too.
+type StateStack struct {
+	fd      uintptr
+	mu      sync.Mutex
+	entries []*stateEntry
+}
+
+type stateEntry struct {
+	state    *State
+	released bool
+}
+
+// NewStateStack returns a StateStack for the terminal connected to the given
+// file descriptor.
+func NewStateStack(fd uintptr) *StateStack {
+	return &StateStack{fd: fd}
+}
+
+// Push changes the mode of the terminal with change, e.g. MakeRaw or
+// SetCbreak, and returns the function that undoes it.
+func (s *StateStack) Push(change func(fd uintptr) (*State, error)) (pop func() error, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := change(s.fd)
+	if err != nil {
+		return nil, err
+	}
+	entry := &stateEntry{state: state}
+	s.entries = append(s.entries, entry)
+
+	var once sync.Once
+	return func() error {
+		var err error
+		once.Do(func() {
+			err = s.release(entry)
+		})
+		return err
+	}, nil
+}
+
+// Depth returns the number of changes that are still in effect.
+func (s *StateStack) Depth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// release marks entry as undone and restores the state found by the oldest of
+// the undone changes at the top of the stack.
+func (s *StateStack) release(entry *stateEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.released = true
+	var restore *State
+	for len(s.entries) > 0 && s.entries[len(s.entries)-1].released {
+		restore = s.entries[len(s.entries)-1].state
+		s.entries = s.entries[:len(s.entries)-1]
+	}
+	if restore == nil {
+		return nil
+	}
+	return RestoreTerminal(s.fd, restore)
+}
@@ -0,0 +1,126 @@
+// +build windows
+
+package term
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+)
+
+// ERROR_INVALID_HANDLE is returned by console functions called with a handle
+// to a console the process is no longer attached to.
+const ERROR_INVALID_HANDLE syscall.Errno = 6
+
+var ErrConsoleDetached = errors.New("The process is not attached to a console")
+
+var stdHandles = struct {
+	sync.Mutex
+	handles map[int]syscall.Handle
+	// stale maps the handles dropped by RefreshStdHandles to their standard
+	// device, for callers still holding them, e.g. as os.Stdout.
+	stale map[syscall.Handle]int
+}{handles: make(map[int]syscall.Handle), stale: make(map[syscall.Handle]int)}
+
+var stdDevices = []int{syscall.STD_INPUT_HANDLE, syscall.STD_OUTPUT_HANDLE, syscall.STD_ERROR_HANDLE}
+
+// GetStdHandle returns the handle of the given standard device
+// (syscall.STD_INPUT_HANDLE, STD_OUTPUT_HANDLE or STD_ERROR_HANDLE). Handles
+// are looked up once and cached until RefreshStdHandles is called.
+func GetStdHandle(std int) (uintptr, error) {
+	stdHandles.Lock()
+	defer stdHandles.Unlock()
+	return getStdHandle(std)
+}
+
+// getStdHandle implements GetStdHandle, with stdHandles locked.
+func getStdHandle(std int) (uintptr, error) {
+	if h, ok := stdHandles.handles[std]; ok {
+		return uintptr(h), nil
+	}
+	h, err := syscall.GetStdHandle(std)
+	if err != nil {
+		return 0, err
+	}
+	if h == 0 || h == syscall.InvalidHandle {
+		return 0, ErrConsoleDetached
+	}
+	stdHandles.handles[std] = h
+	// The value of a closed handle may be reused for the new one.
+	delete(stdHandles.stale, h)
+	return uintptr(h), nil
+}
+
+// RefreshStdHandles drops the cached standard handles, which is needed after
+// the process detaches from its console or attaches to another one.
+func RefreshStdHandles() {
+	stdHandles.Lock()
+	// Forget the stale handles that have been closed since: their values
+	// may be reused by unrelated handles.
+	for h := range stdHandles.stale {
+		if _, err := syscall.GetFileType(h); err == ERROR_INVALID_HANDLE {
+			delete(stdHandles.stale, h)
+		}
+	}
+	for std, h := range stdHandles.handles {
+		stdHandles.stale[h] = std
+	}
+	stdHandles.handles = make(map[int]syscall.Handle)
+	stdHandles.Unlock()
+}
+
+// WithStdHandle calls f with the handle of the given standard device. If f
+// fails because the handle is no longer valid and the device has a new
+// handle, the handles are looked up again and f retried once;
+// ErrConsoleDetached is returned if that fails too.
+func WithStdHandle(std int, f func(fd uintptr) error) error {
+	fd, err := GetStdHandle(std)
+	if err != nil {
+		return err
+	}
+	if err := f(fd); err != ERROR_INVALID_HANDLE {
+		return err
+	}
+
+	// The console functions fail the same way for a handle that isn't a
+	// console, e.g. redirected to a file: only retry if the handle changed.
+	current, err := syscall.GetStdHandle(std)
+	if err != nil || current == 0 || current == syscall.InvalidHandle {
+		return ErrConsoleDetached
+	}
+	if uintptr(current) == fd {
+		return ERROR_INVALID_HANDLE
+	}
+	RefreshStdHandles()
+	if fd, err = GetStdHandle(std); err != nil {
+		return err
+	}
+	if err := f(fd); err != ERROR_INVALID_HANDLE {
+		return err
+	}
+	return ErrConsoleDetached
+}
+
+// withHandle calls f with fd. If fd is, or was before the console was
+// reattached, the handle of a standard device, f goes through WithStdHandle
+// so that it recovers from the reattachment.
+func withHandle(fd uintptr, f func(fd uintptr) error) error {
+	if std, ok := stdDeviceOf(fd); ok {
+		return WithStdHandle(std, f)
+	}
+	return f(fd)
+}
+
+// stdDeviceOf returns the standard device fd is, or was, the handle of.
+func stdDeviceOf(fd uintptr) (int, bool) {
+	stdHandles.Lock()
+	defer stdHandles.Unlock()
+
+	for _, std := range stdDevices {
+		if h, err := getStdHandle(std); err == nil && h == fd {
+			return std, true
+		}
+	}
+	std, ok := stdHandles.stale[syscall.Handle(fd)]
+	return std, ok
+}
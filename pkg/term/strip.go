@@ -0,0 +1,51 @@
+package term
+
+import (
+	"bytes"
+	"io"
+)
+
+// NewStripWriter returns a writer that removes escape sequences and control
+// characters other than tab, carriage return and line feed from the output
+// written to it, e.g. to store logs of a container writing colors as plain
+// text.
+func NewStripWriter(w io.Writer) *StripWriter {
+	s := &StripWriter{w: w}
+	s.seq = s.scratch[:0]
+	return s
+}
+
+// StripWriter is the writer returned by NewStripWriter.
+type StripWriter struct {
+	w io.Writer
+	escapeParser
+	// out is reused across writes for the stripped output.
+	out bytes.Buffer
+}
+
+func (s *StripWriter) Write(p []byte) (int, error) {
+	out := &s.out
+	out.Reset()
+	for i := 0; i < len(p); i++ {
+		if s.idle() {
+			for ; i < len(p) && p[i] != '\x1b'; i++ {
+				if b := p[i]; b >= 0x20 && b != 0x7f || b == '\t' || b == '\r' || b == '\n' {
+					out.WriteByte(b)
+				}
+			}
+			if i == len(p) {
+				break
+			}
+		}
+		s.next(p[i])
+	}
+
+	_, err := s.w.Write(out.Bytes())
+	if out.Cap() > maxRetainedOutput {
+		s.out = bytes.Buffer{}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
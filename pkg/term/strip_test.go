@@ -0,0 +1,40 @@
+package term
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStripWriter(t *testing.T) {
+	for _, test := range []struct {
+		writes   []string
+		expected string
+	}{
+		{[]string{"plain text\n"}, "plain text\n"},
+		{[]string{"\x1b[1;31mred\x1b[0m\r\n"}, "red\r\n"},
+		{[]string{"\x1b]0;title\x07a\x1b]8;;http://x\x1b\\b\x1bP1$r\x1b\\c"}, "abc"},
+		{[]string{"tab\there\a\b\x00\x7f"}, "tab\there"},
+		{[]string{"\x1b[3", "2mgreen\x1b", "(B\x1b[0", "m"}, "green"},
+		{[]string{"caf\xc3", "\xa9 \x1b7\x1b8ok"}, "caf\xc3\xa9 ok"},
+	} {
+		var buf bytes.Buffer
+		w := NewStripWriter(&buf)
+		for _, s := range test.writes {
+			if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+				t.Fatalf("%q: Write returned %d, %v", test.writes, n, err)
+			}
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.writes, test.expected, buf.String())
+		}
+	}
+}
+
+func TestStripWriterLongSequence(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStripWriter(&buf)
+	w.Write([]byte("a\x1b]52;c;" + string(bytes.Repeat([]byte("x"), 2*maxSequenceLength)) + "\x07b"))
+	if buf.String() != "ab" {
+		t.Fatalf("expected %q, got %q", "ab", buf.String())
+	}
+}
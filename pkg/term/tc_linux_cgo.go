@@ -16,6 +16,9 @@ type Termios syscall.Termios
 // mode and returns the previous state of the terminal so that it can be
 // restored.
 func MakeRaw(fd uintptr) (*State, error) {
+	if err := checkForeground(fd); err != nil {
+		return nil, err
+	}
 	var oldState State
 	if err := tcget(fd, &oldState.termios); err != 0 {
 		return nil, err
@@ -23,7 +26,7 @@ func MakeRaw(fd uintptr) (*State, error) {
 
 	newState := oldState.termios
 
-	C.cfmakeraw((*C.struct_termios)(unsafe.Pointer(&newState)))
+	makeRaw(&newState)
 	if err := tcset(fd, &newState); err != 0 {
 		return nil, err
 	}
@@ -38,6 +41,14 @@ func tcget(fd uintptr, p *Termios) syscall.Errno {
 	return 0
 }
 
+func tcflushInput(fd uintptr) syscall.Errno {
+	ret, err := C.tcflush(C.int(fd), C.TCIFLUSH)
+	if ret != 0 {
+		return err.(syscall.Errno)
+	}
+	return 0
+}
+
 func tcset(fd uintptr, p *Termios) syscall.Errno {
 	ret, err := C.tcsetattr(C.int(fd), C.TCSANOW, (*C.struct_termios)(unsafe.Pointer(p)))
 	if ret != 0 {
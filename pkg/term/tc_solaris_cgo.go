@@ -0,0 +1,111 @@
+// +build solaris,cgo
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// #include <termios.h>
+// #include <sys/ioctl.h>
+// #include <fcntl.h>
+// #include <unistd.h>
+//
+// // ioctl is variadic and can't be called from Go directly
+// static int getwinsize(int fd, struct winsize *ws) { return ioctl(fd, TIOCGWINSZ, ws); }
+// static int setwinsize(int fd, struct winsize *ws) { return ioctl(fd, TIOCSWINSZ, ws); }
+// static int getfl(int fd) { return fcntl(fd, F_GETFL); }
+import "C"
+
+type Termios syscall.Termios
+
+// MakeRaw put the terminal connected to the given file descriptor into raw
+// mode and returns the previous state of the terminal so that it can be
+// restored.
+func MakeRaw(fd uintptr) (*State, error) {
+	if err := checkForeground(fd); err != nil {
+		return nil, err
+	}
+	var oldState State
+	if err := tcget(fd, &oldState.termios); err != 0 {
+		return nil, err
+	}
+
+	newState := oldState.termios
+
+	// cfmakeraw is missing from older Solaris releases
+	raw := RawMode()
+	newState.Iflag &^= uint32(raw.IflagClear)
+	newState.Oflag &^= uint32(raw.OflagClear)
+	newState.Lflag &^= uint32(raw.LflagClear)
+	newState.Cflag &^= uint32(raw.CflagClear)
+	newState.Cflag |= uint32(raw.CflagSet)
+	newState.Cc[syscall.VMIN] = 1
+	newState.Cc[syscall.VTIME] = 0
+
+	if err := tcset(fd, &newState); err != 0 {
+		return nil, err
+	}
+	return &oldState, nil
+}
+
+func tcget(fd uintptr, p *Termios) syscall.Errno {
+	ret, err := C.tcgetattr(C.int(fd), (*C.struct_termios)(unsafe.Pointer(p)))
+	if ret != 0 {
+		return err.(syscall.Errno)
+	}
+	return 0
+}
+
+func tcset(fd uintptr, p *Termios) syscall.Errno {
+	ret, err := C.tcsetattr(C.int(fd), C.TCSANOW, (*C.struct_termios)(unsafe.Pointer(p)))
+	if ret != 0 {
+		return err.(syscall.Errno)
+	}
+	return 0
+}
+
+func tcflushInput(fd uintptr) syscall.Errno {
+	ret, err := C.tcflush(C.int(fd), C.TCIFLUSH)
+	if ret != 0 {
+		return err.(syscall.Errno)
+	}
+	return 0
+}
+
+func tcgetwinsize(fd uintptr, ws *Winsize) syscall.Errno {
+	ret, err := C.getwinsize(C.int(fd), (*C.struct_winsize)(unsafe.Pointer(ws)))
+	if ret != 0 {
+		return err.(syscall.Errno)
+	}
+	return 0
+}
+
+func tcsetwinsize(fd uintptr, ws *Winsize) syscall.Errno {
+	ret, err := C.setwinsize(C.int(fd), (*C.struct_winsize)(unsafe.Pointer(ws)))
+	if ret != 0 {
+		return err.(syscall.Errno)
+	}
+	return 0
+}
+
+func getFileFlags(fd uintptr) (int, syscall.Errno) {
+	ret, err := C.getfl(C.int(fd))
+	if ret < 0 {
+		return 0, err.(syscall.Errno)
+	}
+	return int(ret), 0
+}
+
+func tcgetpgrp(fd uintptr) (int, syscall.Errno) {
+	ret, err := C.tcgetpgrp(C.int(fd))
+	if ret < 0 {
+		return 0, err.(syscall.Errno)
+	}
+	return int(ret), 0
+}
+
+func getpgrp() int {
+	return int(C.getpgrp())
+}
@@ -0,0 +1,52 @@
+// +build solaris,!cgo
+
+package term
+
+import "syscall"
+
+// Without cgo, the termios functions of the Solaris libc can't be reached:
+// the syscall package doesn't export ioctl on Solaris. Every file descriptor
+// then looks like it isn't a terminal, as with output redirected to a file.
+
+type Termios syscall.Termios
+
+// MakeRaw put the terminal connected to the given file descriptor into raw
+// mode and returns the previous state of the terminal so that it can be
+// restored. It always fails when built without cgo.
+func MakeRaw(fd uintptr) (*State, error) {
+	return nil, syscall.ENOTSUP
+}
+
+func tcget(fd uintptr, p *Termios) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+func tcset(fd uintptr, p *Termios) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+func tcflushInput(fd uintptr) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+func tcgetwinsize(fd uintptr, ws *Winsize) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+func tcsetwinsize(fd uintptr, ws *Winsize) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+func getFileFlags(fd uintptr) (int, syscall.Errno) {
+	return 0, syscall.ENOTSUP
+}
+
+// tcgetpgrp fails so that IsForeground treats every descriptor as one that
+// isn't the controlling terminal.
+func tcgetpgrp(fd uintptr) (int, syscall.Errno) {
+	return 0, syscall.ENOTSUP
+}
+
+func getpgrp() int {
+	return 0
+}
@@ -7,7 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"unsafe"
+	"time"
 )
 
 var (
@@ -18,16 +18,9 @@ type State struct {
 	termios Termios
 }
 
-type Winsize struct {
-	Height uint16
-	Width  uint16
-	x      uint16
-	y      uint16
-}
-
 func GetWinsize(fd uintptr) (*Winsize, error) {
 	ws := &Winsize{}
-	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(ws)))
+	err := tcgetwinsize(fd, ws)
 	// Skipp errno = 0
 	if err == 0 {
 		return ws, nil
@@ -36,7 +29,7 @@ func GetWinsize(fd uintptr) (*Winsize, error) {
 }
 
 func SetWinsize(fd uintptr, ws *Winsize) error {
-	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCSWINSZ), uintptr(unsafe.Pointer(ws)))
+	err := tcsetwinsize(fd, ws)
 	// Skipp errno = 0
 	if err == 0 {
 		return nil
@@ -44,6 +37,48 @@ func SetWinsize(fd uintptr, ws *Winsize) error {
 	return err
 }
 
+// Termios returns a copy of the terminal attributes saved in s.
+func (s *State) Termios() Termios {
+	return s.termios
+}
+
+// GetControlChars returns the line-editing characters configured for the
+// terminal connected to the given file descriptor.
+func GetControlChars(fd uintptr) (*ControlChars, error) {
+	var termios Termios
+	if err := tcget(fd, &termios); err != 0 {
+		return nil, err
+	}
+	return &ControlChars{
+		Erase: termios.Cc[syscall.VERASE],
+		Kill:  termios.Cc[syscall.VKILL],
+		EOF:   termios.Cc[syscall.VEOF],
+	}, nil
+}
+
+// Tcgetattr returns the attributes of the terminal connected to the given file
+// descriptor, control characters included.
+func Tcgetattr(fd uintptr) (*Termios, error) {
+	var termios Termios
+	if err := tcget(fd, &termios); err != 0 {
+		return nil, err
+	}
+	return &termios, nil
+}
+
+// Tcsetattr sets the attributes of the terminal connected to the given file
+// descriptor, to change flags MakeRaw and the other modes don't cover. Save the
+// state first with SaveState to restore it with RestoreTerminal afterwards.
+func Tcsetattr(fd uintptr, termios *Termios) error {
+	if err := checkForeground(fd); err != nil {
+		return err
+	}
+	if err := tcset(fd, termios); err != 0 {
+		return err
+	}
+	return nil
+}
+
 // IsTerminal returns true if the given file descriptor is a terminal.
 func IsTerminal(fd uintptr) bool {
 	var termios Termios
@@ -62,6 +97,12 @@ func RestoreTerminal(fd uintptr, state *State) error {
 	return nil
 }
 
+// OpenTerminalInput opens the controlling terminal of the process, so that
+// prompts such as password input can reach the user when stdin is redirected.
+func OpenTerminalInput() (*os.File, error) {
+	return os.OpenFile("/dev/tty", os.O_RDWR, 0)
+}
+
 func SaveState(fd uintptr) (*State, error) {
 	var oldState State
 	if err := tcget(fd, &oldState.termios); err != 0 {
@@ -71,7 +112,23 @@ func SaveState(fd uintptr) (*State, error) {
 	return &oldState, nil
 }
 
+// DiscardPendingInput discards the input received by the terminal connected
+// to the given file descriptor that hasn't been read yet, so that keystrokes
+// typed for a prompt don't leak into a raw session started after it.
+func DiscardPendingInput(fd uintptr) error {
+	if err := checkForeground(fd); err != nil {
+		return err
+	}
+	if err := tcflushInput(fd); err != 0 {
+		return err
+	}
+	return nil
+}
+
 func DisableEcho(fd uintptr, state *State) error {
+	if err := checkForeground(fd); err != nil {
+		return err
+	}
 	newState := state.termios
 	newState.Lflag &^= syscall.ECHO
 
@@ -82,6 +139,119 @@ func DisableEcho(fd uintptr, state *State) error {
 	return nil
 }
 
+// SetCbreak puts the terminal connected to the given file descriptor into
+// cbreak mode: input is available a keystroke at a time and not echoed, but
+// Ctrl+C still interrupts and output is still post-processed. It returns the
+// previous state of the terminal so that it can be restored; no signal handler
+// is installed, see RestoreOnExit.
+func SetCbreak(fd uintptr) (*State, error) {
+	if err := checkForeground(fd); err != nil {
+		return nil, err
+	}
+	oldState, err := SaveState(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	newState := oldState.termios
+	newState.Lflag &^= (syscall.ICANON | syscall.ECHO)
+	newState.Cc[syscall.VMIN] = 1
+	newState.Cc[syscall.VTIME] = 0
+
+	if err := tcset(fd, &newState); err != 0 {
+		return nil, err
+	}
+	return oldState, nil
+}
+
+// SetFlowControl turns XON/XOFF flow control (IXON and IXOFF) on or off for the
+// terminal connected to the given file descriptor, regardless of raw mode, so
+// that an accidental Ctrl+S doesn't freeze followed output. It returns the
+// previous state of the terminal so that it can be restored.
+func SetFlowControl(fd uintptr, on bool) (*State, error) {
+	if err := checkForeground(fd); err != nil {
+		return nil, err
+	}
+	oldState, err := SaveState(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	newState := oldState.termios
+	if on {
+		newState.Iflag |= (syscall.IXON | syscall.IXOFF)
+	} else {
+		newState.Iflag &^= (syscall.IXON | syscall.IXOFF)
+	}
+
+	if err := tcset(fd, &newState); err != 0 {
+		return nil, err
+	}
+	return oldState, nil
+}
+
+// SetReadParams sets VMIN and VTIME, which control when reads from the
+// terminal connected to the given file descriptor return in raw or cbreak
+// mode: once min bytes are available or, when timeout is not zero, after no
+// byte arrived for that long (or, with a min of 0, since the read started).
+// The timeout has a resolution of a tenth of a second and is at most 25.5s.
+func SetReadParams(fd uintptr, min uint8, timeout time.Duration) error {
+	if err := checkForeground(fd); err != nil {
+		return err
+	}
+	vtime := (timeout + 100*time.Millisecond - 1) / (100 * time.Millisecond)
+	if timeout < 0 || vtime > 255 {
+		return syscall.EINVAL
+	}
+
+	var termios Termios
+	if err := tcget(fd, &termios); err != 0 {
+		return err
+	}
+	termios.Cc[syscall.VMIN] = min
+	termios.Cc[syscall.VTIME] = uint8(vtime)
+	if err := tcset(fd, &termios); err != 0 {
+		return err
+	}
+	return nil
+}
+
+// MakeRawInput is like MakeRaw, except that output post-processing (OPOST,
+// ONLCR) is left as it was, so that bare line feeds written to the terminal
+// still start a new line.
+func MakeRawInput(fd uintptr) (*State, error) {
+	oldState, err := MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	var newState Termios
+	if err := tcget(fd, &newState); err != 0 {
+		RestoreTerminal(fd, oldState)
+		return nil, err
+	}
+	newState.Oflag = oldState.termios.Oflag
+	if err := tcset(fd, &newState); err != 0 {
+		RestoreTerminal(fd, oldState)
+		return nil, err
+	}
+	return oldState, nil
+}
+
+// MakeRawWithReadParams is like MakeRaw, with the read parameters set as by
+// SetReadParams.
+func MakeRawWithReadParams(fd uintptr, min uint8, timeout time.Duration) (*State, error) {
+	oldState, err := MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	if err := SetReadParams(fd, min, timeout); err != nil {
+		RestoreTerminal(fd, oldState)
+		return nil, err
+	}
+	return oldState, nil
+}
+
 func SetRawTerminal(fd uintptr) (*State, error) {
 	oldState, err := MakeRaw(fd)
 	if err != nil {
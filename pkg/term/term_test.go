@@ -0,0 +1,548 @@
+// +build !windows
+
+package term
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kr/pty"
+)
+
+func openPty(t *testing.T) (master, slave *os.File) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Skipf("Cannot allocate a pty: %s", err)
+	}
+	return master, slave
+}
+
+// saveEnv returns a function setting the environment variable key back to
+// its current value, or unsetting it if it isn't set.
+func saveEnv(key string) (restore func()) {
+	value, ok := os.LookupEnv(key)
+	return func() {
+		if ok {
+			os.Setenv(key, value)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestWinsizeRoundTrip(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	for _, ws := range []Winsize{{Height: 25, Width: 80}, {Height: 1, Width: 1}, {Height: 300, Width: 1000}, {Height: 24, Width: 80, Xpixel: 640, Ypixel: 384}} {
+		if err := SetWinsize(slave.Fd(), &ws); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := GetWinsize(slave.Fd())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *actual != ws {
+			t.Fatalf("GetWinsize() = %v after SetWinsize(%v)", *actual, ws)
+		}
+		// both ends of the pty share the size
+		if actual, err = GetWinsize(master.Fd()); err != nil || *actual != ws {
+			t.Fatalf("GetWinsize() on the master = %v, %v, expected %v", actual, err, ws)
+		}
+	}
+}
+
+func TestSetCbreak(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	oldState, err := SetCbreak(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var termios Termios
+	if err := tcget(slave.Fd(), &termios); err != 0 {
+		t.Fatal(err)
+	}
+	if termios.Lflag&(syscall.ICANON|syscall.ECHO) != 0 {
+		t.Fatalf("ICANON or ECHO still set in cbreak mode: %#x", termios.Lflag)
+	}
+	if termios.Lflag&syscall.ISIG == 0 || termios.Oflag&syscall.OPOST == 0 {
+		t.Fatalf("ISIG or OPOST cleared in cbreak mode: lflag %#x, oflag %#x", termios.Lflag, termios.Oflag)
+	}
+
+	if err := RestoreTerminal(slave.Fd(), oldState); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcget(slave.Fd(), &termios); err != 0 {
+		t.Fatal(err)
+	}
+	if termios != oldState.termios {
+		t.Fatal("RestoreTerminal did not restore the saved state")
+	}
+}
+
+func TestMakeRawWithReadParams(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	oldState, err := MakeRawWithReadParams(slave.Fd(), 0, 150*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RestoreTerminal(slave.Fd(), oldState)
+
+	var termios Termios
+	if err := tcget(slave.Fd(), &termios); err != 0 {
+		t.Fatal(err)
+	}
+	if termios.Cc[syscall.VMIN] != 0 || termios.Cc[syscall.VTIME] != 2 {
+		t.Fatalf("VMIN = %d, VTIME = %d, expected 0 and 2", termios.Cc[syscall.VMIN], termios.Cc[syscall.VTIME])
+	}
+
+	// with VMIN 0, a read with nothing to read returns after the timeout
+	start := time.Now()
+	n, _ := syscall.Read(int(slave.Fd()), make([]byte, 1))
+	if n != 0 || time.Since(start) < 100*time.Millisecond {
+		t.Fatalf("read returned %d bytes after %s", n, time.Since(start))
+	}
+
+	if err := SetReadParams(slave.Fd(), 1, 30*time.Second); err != syscall.EINVAL {
+		t.Fatalf("expected EINVAL for a timeout above 25.5s, got %v", err)
+	}
+}
+
+func TestSetFlowControl(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	for _, on := range []bool{true, false} {
+		oldState, err := SetFlowControl(slave.Fd(), on)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var termios Termios
+		if err := tcget(slave.Fd(), &termios); err != 0 {
+			t.Fatal(err)
+		}
+		flags := termios.Iflag & (syscall.IXON | syscall.IXOFF)
+		if on && flags != syscall.IXON|syscall.IXOFF || !on && flags != 0 {
+			t.Fatalf("SetFlowControl(%v) left iflag %#x", on, termios.Iflag)
+		}
+		if err := RestoreTerminal(slave.Fd(), oldState); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRestoreOnPanic(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	oldState, err := MakeRaw(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	unregister := RestoreOnExit(slave.Fd(), oldState)
+	defer unregister()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("RestoreOnPanic swallowed the panic")
+			}
+		}()
+		defer RestoreOnPanic()
+		panic("crash in raw mode")
+	}()
+
+	var termios Termios
+	if err := tcget(slave.Fd(), &termios); err != 0 {
+		t.Fatal(err)
+	}
+	if termios != oldState.termios {
+		t.Fatal("RestoreOnPanic did not restore the saved state")
+	}
+}
+
+func TestTcsetattrRoundTrip(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	oldState, err := SaveState(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	termios, err := Tcgetattr(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *termios != oldState.Termios() {
+		t.Fatal("Tcgetattr and SaveState disagree")
+	}
+
+	termios.Oflag &^= syscall.ONLCR
+	termios.Cc[syscall.VMIN] = 3
+	if err := Tcsetattr(slave.Fd(), termios); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Tcgetattr(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *termios {
+		t.Fatalf("Tcgetattr returned %+v after setting %+v", got, termios)
+	}
+
+	if err := RestoreTerminal(slave.Fd(), oldState); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetNonblock(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	// slave.Fd() puts the file descriptor back into blocking mode
+	fd := slave.Fd()
+	restore, err := SetNonblock(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf [1]byte
+	if _, err := syscall.Read(int(fd), buf[:]); err != syscall.EAGAIN {
+		t.Fatalf("read from empty non-blocking terminal returned %v, expected EAGAIN", err)
+	}
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	if flags, err := getFileFlags(fd); err != 0 || flags&syscall.O_NONBLOCK != 0 {
+		t.Fatalf("O_NONBLOCK still set after restore: flags %#x, error %v", flags, err)
+	}
+}
+
+func TestIsForegroundNotControllingTerminal(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	if !IsForeground(slave.Fd()) {
+		t.Fatal("IsForeground returned false for a terminal that isn't the controlling terminal")
+	}
+	if err := checkForeground(slave.Fd()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMakeRawInput(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	oldState, err := MakeRawInput(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RestoreTerminal(slave.Fd(), oldState)
+
+	termios, err := Tcgetattr(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if termios.Lflag&(syscall.ICANON|syscall.ECHO|syscall.ISIG) != 0 {
+		t.Fatalf("input is not raw: lflag %#x", termios.Lflag)
+	}
+	if termios.Oflag != oldState.termios.Oflag {
+		t.Fatalf("output flags changed from %#x to %#x", oldState.termios.Oflag, termios.Oflag)
+	}
+}
+
+func TestGetControlChars(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	oldState, err := SaveState(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer RestoreTerminal(slave.Fd(), oldState)
+
+	termios := oldState.Termios()
+	termios.Cc[syscall.VERASE] = 0x08
+	termios.Cc[syscall.VKILL] = 0x18
+	termios.Cc[syscall.VEOF] = 0x04
+	if err := Tcsetattr(slave.Fd(), &termios); err != nil {
+		t.Fatal(err)
+	}
+
+	chars, err := GetControlChars(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (ControlChars{Erase: 0x08, Kill: 0x18, EOF: 0x04}); *chars != expected {
+		t.Fatalf("GetControlChars() = %+v, expected %+v", *chars, expected)
+	}
+}
+
+func TestMonitorSize(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	if err := SetWinsize(slave.Fd(), &Winsize{Height: 24, Width: 80}); err != nil {
+		t.Fatal(err)
+	}
+	sizes := make(chan Winsize, 10)
+	stop := MonitorSize(slave.Fd(), func(ws Winsize) { sizes <- ws })
+	defer stop()
+
+	if ws := <-sizes; ws.Height != 24 || ws.Width != 80 {
+		t.Fatalf("initial size %v, expected 80x24", ws)
+	}
+
+	// a burst of changes, as when dragging a window, is reported once
+	for width := uint16(81); width <= 90; width++ {
+		if err := SetWinsize(slave.Fd(), &Winsize{Height: 24, Width: width}); err != nil {
+			t.Fatal(err)
+		}
+		syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+	}
+	select {
+	case ws := <-sizes:
+		if ws.Width != 90 {
+			t.Fatalf("size %v reported, expected the final width of 90", ws)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("resize not reported")
+	}
+	select {
+	case ws := <-sizes:
+		t.Fatalf("unexpected extra size %v", ws)
+	case <-time.After(2 * resizeDebounce):
+	}
+}
+
+func TestMonitorTtySize(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	if err := SetWinsize(slave.Fd(), &Winsize{Height: 24, Width: 80}); err != nil {
+		t.Fatal(err)
+	}
+	// the process isn't ready to be resized for the first two attempts
+	attempts := 0
+	sizes := make(chan Winsize, 10)
+	stop := MonitorTtySize(slave.Fd(), func(ws Winsize) error {
+		if attempts++; attempts <= 2 {
+			return errors.New("not ready")
+		}
+		sizes <- ws
+		return nil
+	})
+	defer stop()
+
+	select {
+	case ws := <-sizes:
+		if ws.Height != 24 || ws.Width != 80 {
+			t.Fatalf("initial size %v, expected 80x24", ws)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("initial size not sent")
+	}
+
+	if err := SetWinsize(slave.Fd(), &Winsize{Height: 30, Width: 100}); err != nil {
+		t.Fatal(err)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+	select {
+	case ws := <-sizes:
+		if ws.Height != 30 || ws.Width != 100 {
+			t.Fatalf("size %v sent, expected 100x30", ws)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("resize not sent")
+	}
+	select {
+	case ws := <-sizes:
+		t.Fatalf("unexpected extra size %v", ws)
+	case <-time.After(2 * resizeDebounce):
+	}
+}
+
+func TestConsole(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	c, err := NewConsole(slave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Resize(Winsize{Height: 30, Width: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if ws, err := c.Size(); err != nil || ws.Height != 30 || ws.Width != 100 {
+		t.Fatalf("Size() = %v, %v, expected 100x30", ws, err)
+	}
+
+	if err := c.SetRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DisableEcho(); err != nil {
+		t.Fatal(err)
+	}
+	termios, err := Tcgetattr(c.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if termios.Lflag&(syscall.ECHO|syscall.ICANON) != 0 {
+		t.Fatal("expected raw mode without echo")
+	}
+
+	if err := c.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if termios, _ := Tcgetattr(c.Fd()); termios.Lflag&syscall.ICANON == 0 {
+		t.Fatal("Reset didn't restore canonical mode")
+	}
+}
+
+func TestGetCursorPosition(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	go func() {
+		query := make([]byte, 4)
+		if _, err := io.ReadFull(master, query); err != nil || string(query) != "\x1b[6n" {
+			return
+		}
+		master.Write([]byte("typed\x1b[5;12R"))
+	}()
+
+	x, y, err := GetCursorPosition(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x != 11 || y != 4 {
+		t.Fatalf("GetCursorPosition() = %d, %d, expected 11, 4", x, y)
+	}
+}
+
+func TestParseCursorPositionReport(t *testing.T) {
+	for _, c := range []struct {
+		data     string
+		row, col int
+		ok       bool
+	}{
+		{"\x1b[1;1R", 1, 1, true},
+		{"abc\x1b[24;80R", 24, 80, true},
+		{"\x1b[24;80", 0, 0, false},
+		{"\x1b[;80R", 0, 0, false},
+		{"\x1b[24;R", 0, 0, false},
+		{"[24;80R", 0, 0, false},
+	} {
+		row, col, ok := parseCursorPositionReport([]byte(c.data))
+		if row != c.row || col != c.col || ok != c.ok {
+			t.Errorf("parseCursorPositionReport(%q) = %d, %d, %v", c.data, row, col, ok)
+		}
+	}
+}
+
+func TestHideCursor(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	if err := HideCursor(slave.Fd()); err != nil {
+		t.Fatal(err)
+	}
+	// the cursor is shown again if the process dies
+	RestoreAll()
+	if err := ShowCursor(slave.Fd()); err != nil {
+		t.Fatal(err)
+	}
+
+	output := make([]byte, 18)
+	if _, err := io.ReadFull(master, output); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\x1b[?25l\x1b[?25h\x1b[?25h"; string(output) != expected {
+		t.Fatalf("terminal received %q, expected %q", output, expected)
+	}
+}
+
+func TestBell(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	if err := Bell(slave.Fd()); err != nil {
+		t.Fatal(err)
+	}
+	output := make([]byte, 1)
+	if _, err := io.ReadFull(master, output); err != nil || output[0] != '\a' {
+		t.Fatalf("terminal received %q, %v, expected BEL", output, err)
+	}
+}
+
+func TestStateStack(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	original, err := Tcgetattr(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	isRaw := func() bool {
+		termios, err := Tcgetattr(slave.Fd())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return termios.Lflag&syscall.ICANON == 0
+	}
+
+	stack := NewStateStack(slave.Fd())
+	popRaw, err := stack.Push(MakeRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	popCbreak, err := stack.Push(SetCbreak)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unwinding in the wrong order keeps the terminal raw until both are done
+	if err := popRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if !isRaw() || stack.Depth() != 2 {
+		t.Fatalf("outer change undone while the inner one is in effect")
+	}
+	if err := popCbreak(); err != nil {
+		t.Fatal(err)
+	}
+	if stack.Depth() != 0 {
+		t.Fatalf("Depth() = %d after undoing all changes", stack.Depth())
+	}
+	if termios, err := Tcgetattr(slave.Fd()); err != nil || *termios != *original {
+		t.Fatalf("terminal not back to its original state: %v", err)
+	}
+	if err := popRaw(); err != nil {
+		t.Fatal(err)
+	}
+}
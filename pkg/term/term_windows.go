@@ -2,62 +2,160 @@
 
 package term
 
+import (
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+)
+
 type State struct {
 	mode uint32
-}
-
-type Winsize struct {
-	Height uint16
-	Width  uint16
-	x      uint16
-	y      uint16
+	// cygwin is set for Cygwin/MSYS ptys, whose mode is owned by the Cygwin
+	// runtime on the other end of the pipe and cannot be changed from here.
+	cygwin bool
 }
 
 func GetWinsize(fd uintptr) (*Winsize, error) {
-	ws := &Winsize{}
 	var info *CONSOLE_SCREEN_BUFFER_INFO
 	info, err := GetConsoleScreenBufferInfo(fd)
 	if err != nil {
 		return nil, err
 	}
-	ws.Height = uint16(info.srWindow.Right - info.srWindow.Left + 1)
-	ws.Width = uint16(info.srWindow.Bottom - info.srWindow.Top + 1)
-
-	ws.x = 0 // todo azlinux -- this is the pixel size of the Window, and not currently used by any caller
-	ws.y = 0
-
+	ws := WinsizeFromWindow(info.srWindow)
+	if font, err := GetConsoleFont(fd); err == nil {
+		ws.Xpixel = uint16(int(ws.Width) * font.Width)
+		ws.Ypixel = uint16(int(ws.Height) * font.Height)
+	}
 	return ws, nil
 }
 
+// SetWinsize resizes the console window to ws. The screen buffer is made as
+// wide as the window and kept at least as tall as it was, so that scrollback
+// is preserved. The pixel size follows from the console font and is ignored.
 func SetWinsize(fd uintptr, ws *Winsize) error {
-	return nil
+	return setConsoleWinsize(kernel32Console{}, fd, ws)
 }
 
 // IsTerminal returns true if the given file descriptor is a terminal.
 func IsTerminal(fd uintptr) bool {
 	_, e := GetConsoleMode(fd)
-	return e == nil
+	return e == nil || IsCygwinTerminal(fd)
+}
+
+// IsForeground exists for compatibility with other platforms, where it
+// detects processes running in the background of a shell. The console has no
+// job control, so it always returns true.
+func IsForeground(fd uintptr) bool {
+	return true
+}
+
+// IsCygwinTerminal returns true if the given file descriptor is one of the
+// named pipes Cygwin and MSYS use as a pty, e.g. when running under mintty or
+// git-bash. Output to such a terminal is interpreted by the terminal emulator
+// itself, so ANSI sequences can be passed through as is.
+func IsCygwinTerminal(fd uintptr) bool {
+	if t, err := syscall.GetFileType(syscall.Handle(fd)); err != nil || t != syscall.FILE_TYPE_PIPE {
+		return false
+	}
+	name, err := GetFileName(fd)
+	if err != nil {
+		return false
+	}
+	return isCygwinPipeName(name)
+}
+
+// isCygwinPipeName matches pipe names such as
+// \msys-dd50a72ab4668b33-pty0-to-master or \cygwin-e022582115c10879-pty4-from-master
+func isCygwinPipeName(name string) bool {
+	if !strings.HasPrefix(name, `\msys-`) && !strings.HasPrefix(name, `\cygwin-`) {
+		return false
+	}
+	if !strings.HasSuffix(name, "-from-master") && !strings.HasSuffix(name, "-to-master") {
+		return false
+	}
+	return strings.Contains(name, "-pty")
 }
 
 // Restore restores the terminal connected to the given file descriptor to a
 // previous state.
 func RestoreTerminal(fd uintptr, state *State) error {
+	stopInterrupt(fd, state)
+	return setConsoleMode(fd, state)
+}
+
+func setConsoleMode(fd uintptr, state *State) error {
+	if state.cygwin {
+		return nil
+	}
 	return SetConsoleMode(fd, state.mode)
 }
 
+// GetControlChars returns the keys the console uses for line editing in
+// line input mode, which can't be configured: backspace, Escape (which clears
+// the line) and Ctrl+Z.
+func GetControlChars(fd uintptr) (*ControlChars, error) {
+	if !IsTerminal(fd) {
+		return nil, syscall.EINVAL
+	}
+	return &ControlChars{Erase: '\b', Kill: '\x1b', EOF: '\x1a'}, nil
+}
+
+// OpenTerminalInput opens the input buffer of the console of the process, so
+// that prompts such as password input can reach the user when stdin is
+// redirected.
+func OpenTerminalInput() (*os.File, error) {
+	return openConsoleInput()
+}
+
 func SaveState(fd uintptr) (*State, error) {
 	mode, e := GetConsoleMode(fd)
 	if e != nil {
+		if IsCygwinTerminal(fd) {
+			return &State{cygwin: true}, nil
+		}
 		return nil, e
 	}
-	return &State{mode}, nil
+	return &State{mode: mode}, nil
+}
+
+// DiscardPendingInput discards the input events received by the console that
+// haven't been read yet, so that keystrokes typed for a prompt don't leak into
+// a raw session started after it.
+func DiscardPendingInput(fd uintptr) error {
+	return FlushConsoleInputBuffer(fd)
 }
 
 // see http://msdn.microsoft.com/en-us/library/windows/desktop/ms683462(v=vs.85).aspx for these flag settings
 func DisableEcho(fd uintptr, state *State) error {
-	state.mode &^= (ENABLE_ECHO_INPUT)
-	state.mode |= (ENABLE_PROCESSED_INPUT | ENABLE_LINE_INPUT)
-	return SetConsoleMode(fd, state.mode)
+	if state.cygwin {
+		return nil
+	}
+	mode := ApplyConsoleModeOptions(state.mode, false, WithEcho(false), WithProcessedInput(true), WithLineInput(true))
+	if err := SetConsoleMode(fd, mode); err != nil {
+		return err
+	}
+	handleInterrupt(fd, state)
+	return nil
+}
+
+// SetCbreak puts the console connected to the given file descriptor into
+// cbreak mode: input is available a keystroke at a time and not echoed, but
+// Ctrl+C is still processed by the system. It returns the previous state of
+// the console so that it can be restored; no control handler is installed,
+// see RestoreOnExit.
+func SetCbreak(fd uintptr) (*State, error) {
+	state, err := SetConsoleModeOptions(fd, WithEcho(false), WithLineInput(false), WithProcessedInput(true))
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SetFlowControl exists for compatibility with other platforms: the console has
+// no XON/XOFF flow control, so it only returns the state of the console.
+func SetFlowControl(fd uintptr, on bool) (*State, error) {
+	return SaveState(fd)
 }
 
 func SetRawTerminal(fd uintptr) (*State, error) {
@@ -65,10 +163,80 @@ func SetRawTerminal(fd uintptr) (*State, error) {
 	if err != nil {
 		return nil, err
 	}
-	// TODO (azlinux): implement handling interrupt and restore state of terminal
+	handleInterrupt(fd, oldState)
 	return oldState, err
 }
 
+// statusControlCExit is the exit status of a process terminated by Ctrl+C
+// (STATUS_CONTROL_C_EXIT), which the default console handler would have used.
+const statusControlCExit uint32 = 0xC000013A
+
+// interrupts holds the console states to restore on Ctrl+C or Ctrl+Break, and
+// the channel the events are delivered to while there is any.
+var interrupts struct {
+	sync.Mutex
+	states map[uintptr]*State
+	events chan ConsoleEvent
+}
+
+// handleInterrupt restores the console when the process is interrupted with
+// Ctrl+C or Ctrl+Break, so that it isn't left without echo or in raw mode,
+// then exits as the default handler would. It only lasts until the console of
+// fd is restored with RestoreTerminal; closing the console window is handled
+// by RestoreOnExit.
+func handleInterrupt(fd uintptr, state *State) {
+	interrupts.Lock()
+	defer interrupts.Unlock()
+
+	if interrupts.events == nil {
+		events := make(chan ConsoleEvent, 1)
+		if err := NotifyConsoleEvents(events, CTRL_C_EVENT, CTRL_BREAK_EVENT); err != nil {
+			return
+		}
+		interrupts.events = events
+		interrupts.states = make(map[uintptr]*State)
+		go waitInterrupt(events)
+	}
+	interrupts.states[fd] = state
+}
+
+func waitInterrupt(events chan ConsoleEvent) {
+	if _, ok := <-events; !ok {
+		return
+	}
+	interrupts.Lock()
+	for fd, state := range interrupts.states {
+		setConsoleMode(fd, state)
+	}
+	interrupts.Unlock()
+	status := statusControlCExit
+	os.Exit(int(int32(status)))
+}
+
+// stopInterrupt unregisters the state of fd from handleInterrupt, and stops
+// handling the interrupts if no state is left.
+func stopInterrupt(fd uintptr, state *State) {
+	interrupts.Lock()
+	defer interrupts.Unlock()
+
+	if interrupts.events == nil || interrupts.states[fd] != state {
+		return
+	}
+	delete(interrupts.states, fd)
+	if len(interrupts.states) == 0 {
+		StopConsoleEvents(interrupts.events)
+		close(interrupts.events)
+		interrupts.events = nil
+	}
+}
+
+// MakeRawInput is the same as MakeRaw: the console keeps processing output
+// (ENABLE_PROCESSED_OUTPUT) in raw mode, as only the mode of the input buffer
+// is changed.
+func MakeRawInput(fd uintptr) (*State, error) {
+	return MakeRaw(fd)
+}
+
 // MakeRaw puts the terminal connected to the given file descriptor into raw
 // mode and returns the previous state of the terminal so that it can be
 // restored.
@@ -78,10 +246,14 @@ func MakeRaw(fd uintptr) (*State, error) {
 	if err != nil {
 		return nil, err
 	}
+	if state.cygwin {
+		// the pty is put in raw mode by the Cygwin runtime, not by us
+		return state, nil
+	}
 
 	// see http://msdn.microsoft.com/en-us/library/windows/desktop/ms683462(v=vs.85).aspx for these flag settings
-	state.mode &^= (ENABLE_ECHO_INPUT | ENABLE_PROCESSED_INPUT | ENABLE_LINE_INPUT)
-	err = SetConsoleMode(fd, state.mode)
+	mode := ApplyConsoleModeOptions(state.mode, false, WithEcho(false), WithProcessedInput(false), WithLineInput(false))
+	err = SetConsoleMode(fd, mode)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,22 @@
+// +build windows
+
+package term
+
+import "testing"
+
+func TestIsCygwinPipeName(t *testing.T) {
+	for name, expected := range map[string]bool{
+		`\msys-dd50a72ab4668b33-pty0-to-master`:      true,
+		`\msys-dd50a72ab4668b33-pty0-from-master`:    true,
+		`\cygwin-e022582115c10879-pty4-from-master`:  true,
+		`\cygwin-e022582115c10879-pty4-to-master`:    true,
+		`\msys-dd50a72ab4668b33-pty0-to-slave`:       false,
+		`\msys-dd50a72ab4668b33-pipe-to-master`:      false,
+		`\docker_engine`:                             false,
+		`\Users\docker\cygwin-1234-pty0-from-master`: false,
+	} {
+		if actual := isCygwinPipeName(name); actual != expected {
+			t.Errorf("isCygwinPipeName(%q) = %v, expected %v", name, actual, expected)
+		}
+	}
+}
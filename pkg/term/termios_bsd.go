@@ -1,3 +1,5 @@
+// +build freebsd openbsd netbsd
+
 package term
 
 import (
@@ -43,17 +45,21 @@ type Termios struct {
 // mode and returns the previous state of the terminal so that it can be
 // restored.
 func MakeRaw(fd uintptr) (*State, error) {
+	if err := checkForeground(fd); err != nil {
+		return nil, err
+	}
 	var oldState State
 	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(getTermios), uintptr(unsafe.Pointer(&oldState.termios))); err != 0 {
 		return nil, err
 	}
 
 	newState := oldState.termios
-	newState.Iflag &^= (IGNBRK | BRKINT | PARMRK | ISTRIP | INLCR | IGNCR | ICRNL | IXON)
-	newState.Oflag &^= OPOST
-	newState.Lflag &^= (ECHO | ECHONL | ICANON | ISIG | IEXTEN)
-	newState.Cflag &^= (CSIZE | PARENB)
-	newState.Cflag |= CS8
+	raw := RawMode()
+	newState.Iflag &^= uint32(raw.IflagClear)
+	newState.Oflag &^= uint32(raw.OflagClear)
+	newState.Lflag &^= uint32(raw.LflagClear)
+	newState.Cflag &^= uint32(raw.CflagClear)
+	newState.Cflag |= uint32(raw.CflagSet)
 	newState.Cc[syscall.VMIN] = 1
 	newState.Cc[syscall.VTIME] = 0
 
@@ -63,3 +69,9 @@ func MakeRaw(fd uintptr) (*State, error) {
 
 	return &oldState, nil
 }
+
+func tcflushInput(fd uintptr) syscall.Errno {
+	what := syscall.O_RDONLY + 1 // FREAD
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCFLUSH, uintptr(unsafe.Pointer(&what)))
+	return err
+}
@@ -43,19 +43,27 @@ type Termios struct {
 // mode and returns the previous state of the terminal so that it can be
 // restored.
 func MakeRaw(fd uintptr) (*State, error) {
+	if err := checkForeground(fd); err != nil {
+		return nil, err
+	}
 	var oldState State
 	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(getTermios), uintptr(unsafe.Pointer(&oldState.termios))); err != 0 {
 		return nil, err
 	}
 
 	newState := oldState.termios
-	newState.Iflag &^= (IGNBRK | BRKINT | PARMRK | ISTRIP | INLCR | IGNCR | ICRNL | IXON)
-	newState.Oflag &^= OPOST
-	newState.Lflag &^= (ECHO | ECHONL | ICANON | ISIG | IEXTEN)
-	newState.Cflag &^= (CSIZE | PARENB)
-	newState.Cflag |= CS8
+	raw := RawMode()
+	newState.Iflag &^= raw.IflagClear
+	newState.Oflag &^= raw.OflagClear
+	newState.Lflag &^= raw.LflagClear
+	newState.Cflag &^= raw.CflagClear
+	newState.Cflag |= raw.CflagSet
 	newState.Cc[syscall.VMIN] = 1
 	newState.Cc[syscall.VTIME] = 0
+	// as on Linux, so that canonical mode erases whole UTF-8 characters
+	if isUTF8Locale() {
+		newState.Iflag |= syscall.IUTF8
+	}
 
 	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(setTermios), uintptr(unsafe.Pointer(&newState))); err != 0 {
 		return nil, err
@@ -63,3 +71,9 @@ func MakeRaw(fd uintptr) (*State, error) {
 
 	return &oldState, nil
 }
+
+func tcflushInput(fd uintptr) syscall.Errno {
+	what := syscall.O_RDONLY + 1 // FREAD
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCFLUSH, uintptr(unsafe.Pointer(&what)))
+	return err
+}
@@ -0,0 +1,62 @@
+package term
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func TestTermiosLayout(t *testing.T) {
+	// the size of the argument is encoded in the ioctl number
+	if size := uintptr(getTermios>>16) & 0x1fff; unsafe.Sizeof(Termios{}) != size {
+		t.Fatalf("Termios is %d bytes, TIOCGETA expects %d", unsafe.Sizeof(Termios{}), size)
+	}
+	// 4 flags, 20 control characters and padding to align speed_t
+	if offset := unsafe.Offsetof(Termios{}.Ispeed); offset != 56 {
+		t.Fatalf("Ispeed at offset %d, expected 56", offset)
+	}
+}
+
+func TestMakeRawDarwin(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+
+	defer saveEnv("LC_ALL")()
+	os.Setenv("LC_ALL", "en_US.UTF-8")
+
+	oldState, err := MakeRaw(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	termios, err := Tcgetattr(slave.Fd())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if termios.Iflag&(ICRNL|IXON|ISTRIP) != 0 || termios.Oflag&OPOST != 0 {
+		t.Fatalf("input or output processing left on: iflag %#x, oflag %#x", termios.Iflag, termios.Oflag)
+	}
+	if termios.Lflag&(ECHO|ICANON|ISIG|IEXTEN) != 0 {
+		t.Fatalf("local modes left on: lflag %#x", termios.Lflag)
+	}
+	if termios.Cflag&CSIZE != CS8 || termios.Cflag&PARENB != 0 {
+		t.Fatalf("character size not 8 bits without parity: cflag %#x", termios.Cflag)
+	}
+	if termios.Cc[syscall.VMIN] != 1 || termios.Cc[syscall.VTIME] != 0 {
+		t.Fatalf("VMIN = %d, VTIME = %d", termios.Cc[syscall.VMIN], termios.Cc[syscall.VTIME])
+	}
+	if termios.Iflag&syscall.IUTF8 == 0 {
+		t.Fatal("IUTF8 not set with a UTF-8 locale")
+	}
+	if termios.Ispeed != oldState.termios.Ispeed || termios.Ospeed != oldState.termios.Ospeed {
+		t.Fatal("MakeRaw changed the line speed")
+	}
+
+	if err := RestoreTerminal(slave.Fd(), oldState); err != nil {
+		t.Fatal(err)
+	}
+	if termios, err = Tcgetattr(slave.Fd()); err != nil || *termios != oldState.termios {
+		t.Fatalf("RestoreTerminal did not restore the saved state: %v", err)
+	}
+}
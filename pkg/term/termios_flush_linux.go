@@ -0,0 +1,7 @@
+// +build !cgo,!mips,!mipsle,!mips64,!mips64le,!ppc64,!ppc64le
+
+package term
+
+// TCFLSH isn't defined by the syscall package; this is its value on x86, arm,
+// s390x and the other architectures using the generic ioctl numbers.
+const flushTermios = 0x540B
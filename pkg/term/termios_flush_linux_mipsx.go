@@ -0,0 +1,7 @@
+// +build !cgo
+// +build mips mipsle mips64 mips64le
+
+package term
+
+// TCFLSH isn't defined by the syscall package; this is its value on mips.
+const flushTermios = 0x5407
@@ -0,0 +1,7 @@
+// +build !cgo
+// +build ppc64 ppc64le
+
+package term
+
+// TCFLSH isn't defined by the syscall package; this is its value on ppc64.
+const flushTermios = 0x2000741F
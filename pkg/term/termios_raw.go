@@ -0,0 +1,29 @@
+// +build !windows
+
+package term
+
+import "syscall"
+
+// RawModeFlags are the termios flags changed by MakeRaw, the same as with
+// cfmakeraw(3).
+type RawModeFlags struct {
+	IflagClear uint64
+	OflagClear uint64
+	LflagClear uint64
+	CflagClear uint64
+	CflagSet   uint64
+}
+
+// RawMode returns the flags changed by MakeRaw on every Unix platform. IUTF8
+// isn't part of them: on Linux and OS X it is set when the locale uses UTF-8,
+// so that a program switching the terminal back to canonical mode gets whole
+// multibyte characters erased by backspace.
+func RawMode() RawModeFlags {
+	return RawModeFlags{
+		IflagClear: syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON,
+		OflagClear: syscall.OPOST,
+		LflagClear: syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN,
+		CflagClear: syscall.CSIZE | syscall.PARENB,
+		CflagSet:   syscall.CS8,
+	}
+}
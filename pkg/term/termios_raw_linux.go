@@ -0,0 +1,19 @@
+package term
+
+import "syscall"
+
+// makeRaw changes termios to raw mode.
+func makeRaw(termios *Termios) {
+	raw := RawMode()
+	termios.Iflag &^= uint32(raw.IflagClear)
+	termios.Oflag &^= uint32(raw.OflagClear)
+	termios.Lflag &^= uint32(raw.LflagClear)
+	termios.Cflag &^= uint32(raw.CflagClear)
+	termios.Cflag |= uint32(raw.CflagSet)
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+
+	if isUTF8Locale() {
+		termios.Iflag |= syscall.IUTF8
+	}
+}
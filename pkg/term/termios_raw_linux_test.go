@@ -0,0 +1,41 @@
+package term
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestMakeRawIUTF8(t *testing.T) {
+	master, slave := openPty(t)
+	defer master.Close()
+	defer slave.Close()
+	defer saveEnv("LC_ALL")()
+
+	for _, c := range []struct {
+		lang  string
+		iutf8 bool
+	}{
+		{"en_US.UTF-8", true},
+		{"C", false},
+	} {
+		os.Setenv("LC_ALL", c.lang)
+		oldState, err := MakeRaw(slave.Fd())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var termios Termios
+		if err := tcget(slave.Fd(), &termios); err != 0 {
+			t.Fatal(err)
+		}
+		if raw := RawMode(); uint64(termios.Lflag)&raw.LflagClear != 0 || uint64(termios.Oflag)&raw.OflagClear != 0 {
+			t.Fatalf("MakeRaw left flags set: lflag %#x, oflag %#x", termios.Lflag, termios.Oflag)
+		}
+		if iutf8 := termios.Iflag&syscall.IUTF8 != 0; iutf8 != c.iutf8 {
+			t.Fatalf("IUTF8 = %v with LC_ALL=%s", iutf8, c.lang)
+		}
+		if err := RestoreTerminal(slave.Fd(), oldState); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// +build !windows
+
+package term
+
+// SaveTitle exists for compatibility with Windows: the title of a terminal
+// emulator can't be read back, so the returned function does nothing.
+func SaveTitle() (restore func() error) {
+	return func() error { return nil }
+}
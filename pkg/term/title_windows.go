@@ -0,0 +1,59 @@
+// +build windows
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// maxTitleLength is the size of the buffer used to read the console title.
+const maxTitleLength = 4096
+
+var (
+	getConsoleTitleProc = kernel32DLL.NewProc("GetConsoleTitleW")
+	setConsoleTitleProc = kernel32DLL.NewProc("SetConsoleTitleW")
+)
+
+// GetConsoleTitle returns the title of the console window.
+func GetConsoleTitle() (string, error) {
+	buf := make([]uint16, maxTitleLength)
+	r, errno, err := callProcErrno(getConsoleTitleProc, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if err != nil {
+		return "", err
+	}
+	if r == 0 {
+		// an empty title is not an error
+		if errno == 0 {
+			return "", nil
+		}
+		return "", errno
+	}
+	return syscall.UTF16ToString(buf), nil
+}
+
+// SetConsoleTitle sets the title of the console window.
+func SetConsoleTitle(title string) error {
+	p, err := syscall.UTF16PtrFromString(title)
+	if err != nil {
+		return err
+	}
+	if _, err := callProc(setConsoleTitleProc, uintptr(unsafe.Pointer(p))); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SaveTitle saves the title of the console window and returns a function
+// setting it back, for interactive sessions in which a container may change
+// it through an OSC sequence. If the title can't be read, the function does
+// nothing.
+func SaveTitle() (restore func() error) {
+	title, err := GetConsoleTitle()
+	if err != nil {
+		return func() error { return nil }
+	}
+	return func() error {
+		return SetConsoleTitle(title)
+	}
+}
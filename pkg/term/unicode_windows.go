@@ -0,0 +1,92 @@
+// +build windows
+
+package term
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxConsoleWrite is the number of UTF-16 code units handed to WriteConsoleW at
+// once; older consoles reject writes larger than their 64KB heap.
+const maxConsoleWrite = 8192
+
+// unicodeWriter decodes UTF-8 and writes it to the console with WriteConsoleW,
+// so the output doesn't depend on the console code page.
+type unicodeWriter struct {
+	handle  syscall.Handle
+	pending []byte // incomplete UTF-8 sequence left over from the last write
+}
+
+// NewUnicodeWriter returns a writer sending UTF-8 text to out. When out is a
+// console the text is written with WriteConsoleW, otherwise (e.g. when the
+// stream is redirected to a file or a pipe) out is returned unchanged.
+func NewUnicodeWriter(out *os.File) io.Writer {
+	if _, err := GetConsoleMode(out.Fd()); err != nil {
+		return out
+	}
+	return &unicodeWriter{handle: syscall.Handle(out.Fd())}
+}
+
+func (w *unicodeWriter) Write(p []byte) (int, error) {
+	b := p
+	if len(w.pending) > 0 {
+		b = append(w.pending, p...)
+	}
+	b, w.pending = splitIncompleteRune(b)
+	// keep our own copy, the caller owns p
+	w.pending = append([]byte(nil), w.pending...)
+
+	runes := make([]rune, 0, len(b))
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		runes = append(runes, r)
+		b = b[size:]
+	}
+	if err := writeConsoleUTF16(w.handle, utf16.Encode(runes)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func writeConsoleUTF16(handle syscall.Handle, s []uint16) error {
+	for len(s) > 0 {
+		n := len(s)
+		if n > maxConsoleWrite {
+			n = maxConsoleWrite
+			// don't split a surrogate pair across two calls
+			if utf16.IsSurrogate(rune(s[n-1])) && s[n-1] < 0xdc00 {
+				n--
+			}
+		}
+		var written uint32
+		err := withHandle(uintptr(handle), func(fd uintptr) error {
+			return syscall.WriteConsole(syscall.Handle(fd), &s[0], uint32(n), &written, nil)
+		})
+		if err != nil {
+			return err
+		}
+		if written == 0 {
+			return io.ErrShortWrite
+		}
+		s = s[written:]
+	}
+	return nil
+}
+
+// splitIncompleteRune splits b before a trailing UTF-8 sequence that is
+// missing bytes, which will be completed by the next write.
+func splitIncompleteRune(b []byte) (complete, rest []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], b[i:]
+			}
+			break
+		}
+	}
+	return b, nil
+}
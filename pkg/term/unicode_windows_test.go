@@ -0,0 +1,29 @@
+// +build windows
+
+package term
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitIncompleteRune(t *testing.T) {
+	for _, c := range []struct {
+		in, complete, rest string
+	}{
+		{"", "", ""},
+		{"hello", "hello", ""},
+		{"h\xc3\xa9", "h\xc3\xa9", ""},
+		{"h\xc3", "h", "\xc3"},
+		{"h\xe2\x82", "h", "\xe2\x82"},
+		{"h\xf0\x9f\x90", "h", "\xf0\x9f\x90"},
+		{"h\xf0\x9f\x90\xb3", "h\xf0\x9f\x90\xb3", ""},
+		// invalid bytes are left for the decoder to replace
+		{"h\xff", "h\xff", ""},
+	} {
+		complete, rest := splitIncompleteRune([]byte(c.in))
+		if !bytes.Equal(complete, []byte(c.complete)) || !bytes.Equal(rest, []byte(c.rest)) {
+			t.Errorf("splitIncompleteRune(%q) = %q, %q, expected %q, %q", c.in, complete, rest, c.complete, c.rest)
+		}
+	}
+}
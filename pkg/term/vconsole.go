@@ -0,0 +1,302 @@
+package term
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
+// maxSequenceLength bounds the escape sequences held back by the Linux
+// console writer. Longer ones, such as a long title or clipboard contents in
+// an OSC sequence, are consumed up to their end and dropped.
+const maxSequenceLength = 4096
+
+// The Linux console writer keeps buffers up to these sizes across writes;
+// larger ones, needed for a long sequence or a large write, are released
+// afterwards rather than kept for the rest of the session.
+const (
+	scratchSequenceLength = 64
+	maxRetainedOutput     = 64 << 10
+)
+
+// maxSGRParams is the number of SGR parameters the Linux console writer maps.
+// Sequences with more are written unchanged.
+const maxSGRParams = 32
+
+// IsLinuxConsole returns true if the given file descriptor is a Linux virtual
+// console, i.e. the physical console of a server. TERM isn't trusted for it:
+// it stays "linux" in ssh and screen sessions started from the console.
+func IsLinuxConsole(fd uintptr) bool {
+	return isVirtualConsole(fd)
+}
+
+// NewLinuxConsoleWriter returns a writer that adapts the output written to it
+// to the Linux virtual console: xterm window operations and control strings
+// (OSC sequences other than palette changes, DCS, APC...), which the console
+// doesn't support and may display as garbage, are dropped, and 256 and 24-bit
+// colors are mapped to the 8 colors of its palette.
+func NewLinuxConsoleWriter(w io.Writer) *LinuxConsoleWriter {
+	c := &LinuxConsoleWriter{w: w}
+	c.seq = c.scratch[:0]
+	return c
+}
+
+// LinuxConsoleWriter is the writer returned by NewLinuxConsoleWriter.
+type LinuxConsoleWriter struct {
+	w io.Writer
+	escapeParser
+	// out is reused across writes for the translated output.
+	out bytes.Buffer
+}
+
+// escapeParser splits output into text and escape sequences, keeping the state
+// of a sequence split across writes.
+type escapeParser struct {
+	// seq holds an escape sequence split across writes, in scratch unless
+	// it is longer.
+	seq     []byte
+	scratch [scratchSequenceLength]byte
+	// discarding is set while the rest of a sequence longer than
+	// maxSequenceLength is skipped; kind is its second byte, prev the byte
+	// last skipped.
+	discarding bool
+	kind, prev byte
+	truncated  int
+}
+
+// idle returns true if the parser is between escape sequences.
+func (e *escapeParser) idle() bool {
+	return !e.discarding && len(e.seq) == 0
+}
+
+// next feeds b, which is ESC or follows one, to the parser. It returns the
+// escape sequence b completes, if any, which is only valid until the following
+// call. Text between sequences is left to the caller, see idle.
+func (e *escapeParser) next(b byte) []byte {
+	if e.discarding {
+		if sequenceEnds(e.kind, e.prev, b) {
+			e.discarding = false
+		}
+		e.prev = b
+		return nil
+	}
+
+	e.seq = append(e.seq, b)
+	if sequenceComplete(e.seq) {
+		seq := e.seq
+		e.seq = e.scratch[:0]
+		return seq
+	}
+	if len(e.seq) >= maxSequenceLength {
+		e.discarding, e.kind, e.prev = true, e.seq[1], b
+		e.truncated++
+		e.seq = e.scratch[:0]
+	}
+	return nil
+}
+
+// Truncated returns the number of escape sequences dropped because they were
+// longer than the writer can hold.
+func (c *LinuxConsoleWriter) Truncated() int {
+	return c.truncated
+}
+
+func (c *LinuxConsoleWriter) Write(p []byte) (int, error) {
+	if c.idle() && bytes.IndexByte(p, '\x1b') < 0 {
+		// most output, e.g. of docker logs, has no escape sequences at all
+		n, err := c.w.Write(p)
+		atomic.AddInt64(&counters.BytesWritten, int64(n))
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	out := &c.out
+	out.Reset()
+	truncated := c.truncated
+	for i := 0; i < len(p); i++ {
+		if c.idle() {
+			j := bytes.IndexByte(p[i:], '\x1b')
+			if j < 0 {
+				out.Write(p[i:])
+				break
+			}
+			out.Write(p[i : i+j])
+			i += j
+		}
+		if seq := c.next(p[i]); seq != nil {
+			translateForLinuxConsole(out, seq)
+		}
+	}
+	atomic.AddInt64(&counters.SequencesDropped, int64(c.truncated-truncated))
+
+	n, err := c.w.Write(out.Bytes())
+	atomic.AddInt64(&counters.BytesWritten, int64(n))
+	if out.Cap() > maxRetainedOutput {
+		c.out = bytes.Buffer{}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// sequenceComplete returns true if seq, which starts with ESC, is a complete
+// escape sequence.
+func sequenceComplete(seq []byte) bool {
+	if len(seq) < 2 {
+		return false
+	}
+	if seq[1] == ']' && len(seq) > 2 {
+		switch seq[2] {
+		case 'R':
+			// reset palette
+			return true
+		case 'P':
+			// set palette: ESC ] P nrrggbb, without terminator
+			return len(seq) == 10
+		}
+	}
+	if len(seq) == 2 && (seq[1] == '[' || isControlString(seq[1])) {
+		return false
+	}
+	return sequenceEnds(seq[1], seq[len(seq)-2], seq[len(seq)-1])
+}
+
+// sequenceEnds returns true if b is the last byte of an escape sequence of the
+// given kind (the byte after ESC), prev being the byte before b.
+func sequenceEnds(kind, prev, b byte) bool {
+	switch {
+	case kind == '[':
+		return b >= 0x40 && b <= 0x7e
+	case isControlString(kind):
+		// terminated by BEL or ST (ESC \)
+		return b == '\a' || b == '\\' && prev == '\x1b'
+	}
+	// ESC, intermediate bytes, final byte
+	return b >= 0x30 && b <= 0x7e
+}
+
+// isControlString returns true if ESC kind starts a control string: OSC, DCS,
+// APC, PM or SOS.
+func isControlString(kind byte) bool {
+	return kind == ']' || kind == 'P' || kind == '_' || kind == '^' || kind == 'X'
+}
+
+// translateForLinuxConsole writes the complete escape sequence seq to out as
+// the Linux console supports it, possibly not at all.
+func translateForLinuxConsole(out *bytes.Buffer, seq []byte) {
+	atomic.AddInt64(&counters.SequencesTranslated, 1)
+	switch {
+	case seq[1] == ']' && (seq[2] == 'P' || seq[2] == 'R'):
+	case isControlString(seq[1]):
+		atomic.AddInt64(&counters.SequencesDropped, 1)
+		return
+	case seq[1] == '[':
+		switch seq[len(seq)-1] {
+		case 't':
+			// window manipulation
+			atomic.AddInt64(&counters.SequencesDropped, 1)
+			return
+		case 'm':
+			if writeSGRForLinuxConsole(out, seq[2:len(seq)-1]) {
+				return
+			}
+		}
+	}
+	out.Write(seq)
+}
+
+// writeSGRForLinuxConsole writes an SGR sequence with the parameters params
+// to out, changed so that colors are within the 8 colors of the Linux console,
+// bright colors being shown bold. It returns false, writing nothing, if params
+// aren't plain numbers or are too many. It doesn't allocate.
+func writeSGRForLinuxConsole(out *bytes.Buffer, params []byte) bool {
+	var values [maxSGRParams]int
+	n := 0
+	if len(params) > 0 {
+		n = 1
+		for _, b := range params {
+			switch {
+			case b >= '0' && b <= '9':
+				values[n-1] = values[n-1]*10 + int(b-'0')
+			case (b == ';' || b == ':') && n < maxSGRParams:
+				n++
+			default:
+				return false
+			}
+		}
+	}
+
+	out.WriteString("\x1b[")
+	first := true
+	param := func(v int) {
+		var digits [20]byte
+		if !first {
+			out.WriteByte(';')
+		}
+		first = false
+		out.Write(strconv.AppendInt(digits[:0], int64(v), 10))
+	}
+	for i := 0; i < n; i++ {
+		v := values[i]
+		switch {
+		case v >= 90 && v <= 97:
+			param(1)
+			param(v - 60)
+		case v >= 100 && v <= 107:
+			param(v - 60)
+		case (v == 38 || v == 48) && i+2 < n && values[i+1] == 5:
+			color, bright := nearestConsoleColor(values[i+2])
+			if bright && v == 38 {
+				param(1)
+			}
+			param(v - 8 + color)
+			i += 2
+		case (v == 38 || v == 48) && i+4 < n && values[i+1] == 2:
+			param(v - 8 + rgbToConsoleColor(values[i+2], values[i+3], values[i+4]))
+			i += 4
+		default:
+			param(v)
+		}
+	}
+	out.WriteByte('m')
+	return true
+}
+
+// nearestConsoleColor returns the ANSI color (0-7) closest to the given color
+// of the 256-color palette, and whether it is a bright one.
+func nearestConsoleColor(index int) (color int, bright bool) {
+	switch {
+	case index < 8:
+		return index, false
+	case index < 16:
+		return index - 8, true
+	case index < 232:
+		// 6x6x6 color cube
+		index -= 16
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		return rgbToConsoleColor(levels[index/36], levels[index/6%6], levels[index%6]), false
+	}
+	// grayscale ramp
+	level := 8 + (index-232)*10
+	return rgbToConsoleColor(level, level, level), level > 0xd0
+}
+
+// rgbToConsoleColor returns the ANSI color (0-7) closest to the given RGB
+// color.
+func rgbToConsoleColor(r, g, b int) int {
+	color := 0
+	if r > 127 {
+		color |= 1
+	}
+	if g > 127 {
+		color |= 2
+	}
+	if b > 127 {
+		color |= 4
+	}
+	return color
+}
@@ -0,0 +1,40 @@
+// +build gofuzz
+
+package term
+
+import "bytes"
+
+// Fuzz is the entry point for go-fuzz. It checks that the Linux console
+// writer doesn't panic, keeps bounded state, and produces the same output
+// whether data arrives in one write or split in two, and that the strip
+// writer leaves no escape character.
+func Fuzz(data []byte) int {
+	var whole, split bytes.Buffer
+
+	w := NewLinuxConsoleWriter(&whole)
+	if _, err := w.Write(data); err != nil {
+		panic(err)
+	}
+	if len(w.seq) >= maxSequenceLength {
+		panic("sequence held back beyond maxSequenceLength")
+	}
+
+	w = NewLinuxConsoleWriter(&split)
+	half := len(data) / 2
+	w.Write(data[:half])
+	w.Write(data[half:])
+	if !bytes.Equal(whole.Bytes(), split.Bytes()) {
+		panic("output depends on how the input is split")
+	}
+
+	var stripped bytes.Buffer
+	NewStripWriter(&stripped).Write(data)
+	if bytes.IndexByte(stripped.Bytes(), '\x1b') >= 0 {
+		panic("escape character left by the strip writer")
+	}
+
+	if bytes.IndexByte(data, '\x1b') >= 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// KDGKBTYPE gets the keyboard type; only virtual consoles support it.
+const kdgkbtype = 0x4B33
+
+func isVirtualConsole(fd uintptr) bool {
+	var kbtype byte
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, kdgkbtype, uintptr(unsafe.Pointer(&kbtype)))
+	return err == 0
+}
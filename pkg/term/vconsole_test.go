@@ -0,0 +1,132 @@
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestLinuxConsoleWriter(t *testing.T) {
+	for _, c := range []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"plain text\r\n"}, "plain text\r\n"},
+		{[]string{"\x1b]0;title\x07text"}, "text"},
+		{[]string{"\x1b]2;ti", "tle\x1b\\text"}, "text"},
+		{[]string{"\x1b]P", "0102030after"}, "\x1b]P0102030after"},
+		{[]string{"\x1b[8;24;80t\x1b[2J"}, "\x1b[2J"},
+		{[]string{"\x1b[1;31mred\x1b[0m"}, "\x1b[1;31mred\x1b[0m"},
+		{[]string{"\x1b[38;5;", "196mred"}, "\x1b[31mred"},
+		{[]string{"\x1b[38;5;12m\x1b[48;5;10m"}, "\x1b[1;34m\x1b[42m"},
+		{[]string{"\x1b[38;2;255;255;0;48;2;0;0;128m"}, "\x1b[33;44m"},
+		{[]string{"\x1b[92m\x1b[103m"}, "\x1b[1;32m\x1b[43m"},
+		{[]string{"\x1b(B\x1b[?25l"}, "\x1b(B\x1b[?25l"},
+		{[]string{"\x1bPq#0;2;0;0;0\x1b\\text"}, "text"},
+		{[]string{"\x1b7\x1b8"}, "\x1b7\x1b8"},
+		{[]string{"\x1b[;1m\x1b[m"}, "\x1b[0;1m\x1b[m"},
+		{[]string{"\x1b[>4;2m"}, "\x1b[>4;2m"},
+	} {
+		var buf bytes.Buffer
+		w := NewLinuxConsoleWriter(&buf)
+		for _, input := range c.input {
+			if n, err := w.Write([]byte(input)); err != nil || n != len(input) {
+				t.Fatalf("Write(%q) = %d, %v", input, n, err)
+			}
+		}
+		if buf.String() != c.expected {
+			t.Errorf("%q written as %q, expected %q", c.input, buf.String(), c.expected)
+		}
+	}
+}
+
+func TestLinuxConsoleWriterOversizedSequence(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLinuxConsoleWriter(&buf)
+
+	payload := bytes.Repeat([]byte("a"), 3*maxSequenceLength)
+	for _, chunk := range [][]byte{[]byte("before\x1b]52;c;"), payload, []byte("\x1b\\after")} {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf.String() != "beforeafter" {
+		t.Fatalf("oversized OSC sequence written as %q", buf.String())
+	}
+	if w.Truncated() != 1 {
+		t.Fatalf("Truncated() = %d, expected 1", w.Truncated())
+	}
+}
+
+func benchmarkLinuxConsoleWriter(b *testing.B, data []byte) {
+	w := NewLinuxConsoleWriter(ioutil.Discard)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		w.Write(data)
+	}
+}
+
+// BenchmarkLinuxConsoleWriterPlainLog writes log lines without escape
+// sequences, the bulk of `docker logs` output.
+func BenchmarkLinuxConsoleWriterPlainLog(b *testing.B) {
+	benchmarkLinuxConsoleWriter(b, bytes.Repeat([]byte("2014-11-20T10:00:00Z GET /v1.16/containers/json 200\n"), 100))
+}
+
+// BenchmarkLinuxConsoleWriterColoredLog writes log lines with SGR sequences,
+// 256 colors and cursor moves.
+func BenchmarkLinuxConsoleWriterColoredLog(b *testing.B) {
+	benchmarkLinuxConsoleWriter(b, bytes.Repeat([]byte("\x1b[38;5;196merror\x1b[0m \x1b[1;32mok\x1b[0m \x1b[12;40Hdone\r\n"), 100))
+}
+
+// BenchmarkLinuxConsoleWriterRedraw redraws an 80x25 screen the way
+// full-screen programs do: clear, then position and color every row.
+func BenchmarkLinuxConsoleWriterRedraw(b *testing.B) {
+	var screen bytes.Buffer
+	screen.WriteString("\x1b[H\x1b[2J")
+	for row := 1; row <= 25; row++ {
+		fmt.Fprintf(&screen, "\x1b[%d;1H", row)
+		for col := 0; col < 8; col++ {
+			fmt.Fprintf(&screen, "\x1b[%d;%dm%-10s", 30+col, 40+(col+row)%8, "cell")
+		}
+	}
+	benchmarkLinuxConsoleWriter(b, screen.Bytes())
+}
+
+// BenchmarkLinuxConsoleWriterLongOSC writes a window title too long to be
+// kept, which is skipped up to its terminator.
+func BenchmarkLinuxConsoleWriterLongOSC(b *testing.B) {
+	benchmarkLinuxConsoleWriter(b, []byte("\x1b]0;"+strings.Repeat("title ", 2*maxSequenceLength/6)+"\x07"))
+}
+
+func TestLinuxConsoleWriterReleasesLargeBuffers(t *testing.T) {
+	w := NewLinuxConsoleWriter(ioutil.Discard)
+
+	w.Write(append([]byte("\x1b[31m"), bytes.Repeat([]byte("a"), 4*maxRetainedOutput)...))
+	if w.out.Cap() > maxRetainedOutput {
+		t.Fatalf("output buffer of %d bytes kept after a large write", w.out.Cap())
+	}
+	w.Write([]byte("\x1b]0;" + strings.Repeat("t", 10*scratchSequenceLength) + "\x07"))
+	if cap(w.seq) != scratchSequenceLength {
+		t.Fatalf("sequence buffer of %d bytes kept after a long sequence", cap(w.seq))
+	}
+}
+
+func TestLinuxConsoleWriterCounters(t *testing.T) {
+	before := GetCounters()
+	w := NewLinuxConsoleWriter(ioutil.Discard)
+	w.Write([]byte("\x1b[8;24;80t\x1b[31mred\x1b[0m\n"))
+
+	after := GetCounters()
+	if n := after.SequencesTranslated - before.SequencesTranslated; n != 3 {
+		t.Errorf("expected 3 sequences translated, got %d", n)
+	}
+	if n := after.SequencesDropped - before.SequencesDropped; n != 1 {
+		t.Errorf("expected 1 sequence dropped, got %d", n)
+	}
+	if n := after.BytesWritten - before.BytesWritten; n != int64(len("\x1b[31mred\x1b[0m\n")) {
+		t.Errorf("unexpected number of bytes written: %d", n)
+	}
+}
@@ -0,0 +1,7 @@
+// +build !linux
+
+package term
+
+func isVirtualConsole(fd uintptr) bool {
+	return false
+}
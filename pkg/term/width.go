@@ -0,0 +1,123 @@
+package term
+
+import (
+	"sort"
+	"unicode"
+)
+
+// runeRange is an inclusive range of code points.
+type runeRange struct {
+	first, last rune
+}
+
+// wideRanges are the code points displayed in two columns: the East Asian
+// Wide and Fullwidth characters, most of them CJK, and emoji.
+var wideRanges = []runeRange{
+	{0x1100, 0x115f},   // Hangul Jamo initial consonants
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x2329, 0x232a},   // angle brackets
+	{0x23e9, 0x23ec},   // media controls
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass
+	{0x25fd, 0x25fe},   // medium small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac signs
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // medium circles
+	{0x26bd, 0x26be},   // soccer ball, baseball
+	{0x26c4, 0x26c5},   // snowman, sun behind cloud
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f5},   // fountain...sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // raised fist, raised hand
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2795, 0x2797},   // heavy plus, minus, division
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2e80, 0x303e},   // CJK radicals, Kangxi radicals, CJK symbols
+	{0x3041, 0x33ff},   // Hiragana, Katakana, Bopomofo, Hangul compatibility Jamo...
+	{0x3400, 0x4dbf},   // CJK unified ideographs extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms, small forms
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x16fe4}, // ideographic symbols
+	{0x17000, 0x18aff}, // Tangut
+	{0x1b000, 0x1b2ff}, // Kana supplement, Nushu
+	{0x1f004, 0x1f004}, // mahjong tile
+	{0x1f0cf, 0x1f0cf}, // joker
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f200, 0x1f251}, // enclosed ideographic supplement
+	{0x1f300, 0x1f64f}, // pictographs, emoticons
+	{0x1f680, 0x1f6ff}, // transport and map symbols
+	{0x1f7e0, 0x1f7eb}, // colored circles and squares
+	{0x1f90c, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK unified ideographs extensions B-F
+	{0x30000, 0x3fffd}, // CJK unified ideographs extension G
+}
+
+// RuneWidth returns the number of columns r takes on a terminal: 0 for
+// control characters and for characters combining with the previous one, 2
+// for East Asian wide characters and emoji, 1 otherwise. Characters of
+// ambiguous width, such as Greek or box drawing characters, which take two
+// columns on some CJK terminals, count as 1, as they do on most terminals.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || r >= 0x7f && r < 0xa0:
+		return 0
+	case r < 0x300:
+		// no combining or wide characters before the combining diacritics
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || r >= 0x1160 && r <= 0x11ff:
+		// combining marks, format characters such as the zero width
+		// joiner, and Hangul Jamo vowels and trailing consonants,
+		// which combine with the initial consonant; the soft hyphen,
+		// also a format character, is below 0x300
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+func isWide(r rune) bool {
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i].last >= r })
+	return i < len(wideRanges) && wideRanges[i].first <= r
+}
+
+// StringWidth returns the number of columns s takes on a terminal, as the
+// sum of the widths of its characters.
+func StringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// TruncateToWidth returns the longest prefix of s taking at most width
+// columns on a terminal, keeping the characters combining with the last
+// character kept.
+func TruncateToWidth(s string, width int) string {
+	total := 0
+	for i, r := range s {
+		w := RuneWidth(r)
+		if total+w > width {
+			return s[:i]
+		}
+		total += w
+	}
+	return s
+}
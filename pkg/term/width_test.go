@@ -0,0 +1,63 @@
+package term
+
+import "testing"
+
+func TestRuneWidth(t *testing.T) {
+	for r, expected := range map[rune]int{
+		'a':      1,
+		'\t':     0,
+		'\x1b':   0,
+		0x85:     0,
+		0xad:     1, // soft hyphen
+		'é':      1,
+		0x301:    0, // combining acute accent
+		0x200d:   0, // zero width joiner
+		'α':      1, // ambiguous
+		'─':      1, // ambiguous
+		'中':      2,
+		'あ':      2,
+		'한':      2,
+		0x1161:   0, // Hangul Jamo vowel
+		'Ａ':      2, // fullwidth A
+		'ｱ':      1, // halfwidth katakana
+		0x1f600:  2, // grinning face
+		0x2f800:  2, // CJK compatibility ideograph supplement
+		0x10ffff: 1,
+	} {
+		if w := RuneWidth(r); w != expected {
+			t.Errorf("RuneWidth(%U) = %d, expected %d", r, w, expected)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	for s, expected := range map[string]int{
+		"":               0,
+		"docker":         6,
+		"日本語":            6,
+		"e\u0301t\u00e9": 3,
+		"a😀b":            4,
+	} {
+		if w := StringWidth(s); w != expected {
+			t.Errorf("StringWidth(%q) = %d, expected %d", s, w, expected)
+		}
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"docker", 10, "docker"},
+		{"docker", 3, "doc"},
+		{"日本語", 5, "日本"},
+		{"日本語", 1, ""},
+		{"cafe\u0301s", 4, "cafe\u0301"},
+	} {
+		if s := TruncateToWidth(test.s, test.width); s != test.expected {
+			t.Errorf("TruncateToWidth(%q, %d) = %q, expected %q", test.s, test.width, s, test.expected)
+		}
+	}
+}
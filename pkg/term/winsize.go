@@ -0,0 +1,31 @@
+package term
+
+// Winsize is the size of a terminal, laid out as the struct winsize used by
+// the TIOCGWINSZ and TIOCSWINSZ ioctls. Windows fills it from the console
+// window rectangle and font.
+type Winsize struct {
+	// Height and Width are the number of rows and columns.
+	Height uint16
+	Width  uint16
+	// Xpixel and Ypixel are the width and height in pixels, or 0 if unknown.
+	// Programs drawing images, e.g. with sixels, use them to scale their
+	// output.
+	Xpixel uint16
+	Ypixel uint16
+}
+
+// sendLatestWinsize sends ws on c, a channel with a buffer of one, replacing
+// any size the receiver hasn't picked up yet.
+func sendLatestWinsize(c chan Winsize, ws Winsize) {
+	for {
+		select {
+		case c <- ws:
+			return
+		default:
+		}
+		select {
+		case <-c:
+		default:
+		}
+	}
+}
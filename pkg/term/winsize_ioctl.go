@@ -0,0 +1,18 @@
+// +build !windows,!solaris
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func tcgetwinsize(fd uintptr, ws *Winsize) syscall.Errno {
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(ws)))
+	return err
+}
+
+func tcsetwinsize(fd uintptr, ws *Winsize) syscall.Errno {
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCSWINSZ), uintptr(unsafe.Pointer(ws)))
+	return err
+}
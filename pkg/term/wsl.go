@@ -0,0 +1,24 @@
+package term
+
+import (
+	"os"
+	"strings"
+)
+
+// IsWSL returns true if the process runs inside the Windows Subsystem for
+// Linux, where the terminal is a Windows console host that already interprets
+// VT sequences and must not be emulated again. On Windows it reports whether
+// the process was started from WSL, provided WSLENV forwards the WSL
+// variables.
+func IsWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" || os.Getenv("WSL_INTEROP") != "" {
+		return true
+	}
+	return isWSLKernel()
+}
+
+// isWSLRelease returns true for the kernel release strings of WSL, e.g.
+// "4.4.0-19041-Microsoft" or "5.10.16.3-microsoft-standard-WSL2".
+func isWSLRelease(release string) bool {
+	return strings.Contains(strings.ToLower(release), "microsoft")
+}
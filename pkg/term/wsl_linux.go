@@ -0,0 +1,11 @@
+package term
+
+import "io/ioutil"
+
+func isWSLKernel() bool {
+	release, err := ioutil.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return false
+	}
+	return isWSLRelease(string(release))
+}
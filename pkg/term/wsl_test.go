@@ -0,0 +1,16 @@
+package term
+
+import "testing"
+
+func TestIsWSLRelease(t *testing.T) {
+	for release, expected := range map[string]bool{
+		"4.4.0-19041-Microsoft\n":             true,
+		"5.10.16.3-microsoft-standard-WSL2\n": true,
+		"3.16.0-4-amd64\n":                    false,
+		"5.15.0-generic\n":                    false,
+	} {
+		if actual := isWSLRelease(release); actual != expected {
+			t.Errorf("isWSLRelease(%q) = %v, expected %v", release, actual, expected)
+		}
+	}
+}
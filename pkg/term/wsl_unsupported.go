@@ -0,0 +1,7 @@
+// +build !linux
+
+package term
+
+func isWSLKernel() bool {
+	return false
+}
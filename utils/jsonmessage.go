@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -95,14 +96,23 @@ func (jm *JSONMessage) Display(out io.Writer, isTerminal bool) error {
 		}
 		return jm.Error
 	}
-	var endl string
 	if isTerminal && jm.Stream == "" && jm.Progress != nil {
-		// <ESC>[2K = erase entire current line
-		fmt.Fprintf(out, "%c[2K\r", 27)
-		endl = "\r"
+		// update the progress line in place
+		var line bytes.Buffer
+		jm.displayLine(&line, true)
+		width := 0
+		if ws, err := term.GetWinsize(jm.Progress.terminalFd); err == nil {
+			width = int(ws.Width)
+		}
+		return term.WriteProgressLine(out, line.String(), width)
 	} else if jm.Progress != nil && jm.Progress.String() != "" { //disable progressbar in non-terminal
 		return nil
 	}
+	jm.displayLine(out, isTerminal)
+	return nil
+}
+
+func (jm *JSONMessage) displayLine(out io.Writer, isTerminal bool) {
 	if jm.Time != 0 {
 		fmt.Fprintf(out, "%s ", time.Unix(jm.Time, 0).Format(timeutils.RFC3339NanoFixed))
 	}
@@ -113,22 +123,22 @@ func (jm *JSONMessage) Display(out io.Writer, isTerminal bool) error {
 		fmt.Fprintf(out, "(from %s) ", jm.From)
 	}
 	if jm.Progress != nil && isTerminal {
-		fmt.Fprintf(out, "%s %s%s", jm.Status, jm.Progress.String(), endl)
+		fmt.Fprintf(out, "%s %s", jm.Status, jm.Progress.String())
 	} else if jm.ProgressMessage != "" { //deprecated
-		fmt.Fprintf(out, "%s %s%s", jm.Status, jm.ProgressMessage, endl)
+		fmt.Fprintf(out, "%s %s", jm.Status, jm.ProgressMessage)
 	} else if jm.Stream != "" {
-		fmt.Fprintf(out, "%s%s", jm.Stream, endl)
+		fmt.Fprintf(out, "%s", jm.Stream)
 	} else {
-		fmt.Fprintf(out, "%s%s\n", jm.Status, endl)
+		fmt.Fprintf(out, "%s\n", jm.Status)
 	}
-	return nil
 }
 
 func DisplayJSONMessagesStream(in io.Reader, out io.Writer, terminalFd uintptr, isTerminal bool) error {
 	var (
-		dec  = json.NewDecoder(in)
-		ids  = make(map[string]int)
-		diff = 0
+		dec = json.NewDecoder(in)
+		// progress lines, one per ID, are kept at the bottom and updated
+		// in place
+		region = term.NewProgressRegion(out, terminalFd)
 	)
 	for {
 		var jm JSONMessage
@@ -142,29 +152,20 @@ func DisplayJSONMessagesStream(in io.Reader, out io.Writer, terminalFd uintptr,
 		if jm.Progress != nil {
 			jm.Progress.terminalFd = terminalFd
 		}
-		if jm.ID != "" && (jm.Progress != nil || jm.ProgressMessage != "") {
-			line, ok := ids[jm.ID]
-			if !ok {
-				line = len(ids)
-				ids[jm.ID] = line
-				if isTerminal {
-					fmt.Fprintf(out, "\n")
-				}
-				diff = 0
-			} else {
-				diff = len(ids) - line
-			}
-			if jm.ID != "" && isTerminal {
-				// <ESC>[{diff}A = move cursor up diff rows
-				fmt.Fprintf(out, "%c[%dA", 27, diff)
+		if isTerminal && jm.Error == nil && jm.ID != "" {
+			// progress and status messages about the same ID, such as
+			// "Pull complete" after the download progress, share a line
+			var line bytes.Buffer
+			jm.displayLine(&line, isTerminal)
+			if err := region.Update(jm.ID, strings.TrimSuffix(line.String(), "\n")); err != nil {
+				return err
 			}
+			continue
 		}
-		err := jm.Display(out, isTerminal)
-		if jm.ID != "" && isTerminal {
-			// <ESC>[{diff}B = move cursor down diff rows
-			fmt.Fprintf(out, "%c[%dB", 27, diff)
-		}
-		if err != nil {
+		// output without an ID goes below the progress lines, which are
+		// done
+		region.Reset()
+		if err := jm.Display(out, isTerminal); err != nil {
 			return err
 		}
 	}
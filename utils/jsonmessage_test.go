@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -36,3 +38,57 @@ func TestProgress(t *testing.T) {
 		t.Fatalf("Expected %q, got %q", expected, jp4.String())
 	}
 }
+
+func TestDisplayProgressLine(t *testing.T) {
+	var buf bytes.Buffer
+	jm := JSONMessage{ID: "abc", Status: "Downloading", Progress: &JSONProgress{terminalFd: ^uintptr(0), Current: 1}}
+	if err := jm.Display(&buf, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\rabc: Downloading      1 B\x1b[K\r"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestDisplayJSONMessagesStream(t *testing.T) {
+	stream := `{"status":"Pulling repository busybox"}
+{"status":"Downloading","progressDetail":{"current":1},"id":"a"}
+{"status":"Downloading","progressDetail":{"current":1},"id":"b"}
+{"status":"Download complete","progressDetail":{},"id":"a"}
+{"status":"Status: Downloaded newer image for busybox"}
+`
+	var buf bytes.Buffer
+	if err := DisplayJSONMessagesStream(strings.NewReader(stream), &buf, ^uintptr(0), true); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Pulling repository busybox\n" +
+		"\ra: Downloading      1 B\x1b[K\r\n" +
+		"\rb: Downloading      1 B\x1b[K\r\n" +
+		"\x1b[2A\ra: Download complete \x1b[K\r\x1b[2B" +
+		"Status: Downloaded newer image for busybox\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestDisplayJSONMessagesStreamStatusWithID(t *testing.T) {
+	stream := `{"status":"Downloading","progressDetail":{"current":1},"id":"a"}
+{"status":"Downloading","progressDetail":{"current":1},"id":"b"}
+{"status":"Verifying Checksum","id":"b"}
+{"status":"Downloading","progressDetail":{"current":2},"id":"a"}
+{"status":"Already exists","id":"c"}
+`
+	var buf bytes.Buffer
+	if err := DisplayJSONMessagesStream(strings.NewReader(stream), &buf, ^uintptr(0), true); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\ra: Downloading      1 B\x1b[K\r\n" +
+		"\rb: Downloading      1 B\x1b[K\r\n" +
+		"\x1b[1A\rb: Verifying Checksum\x1b[K\r\x1b[1B" +
+		"\x1b[2A\ra: Downloading      2 B\x1b[K\r\x1b[2B" +
+		"\rc: Already exists\x1b[K\r\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, buf.String())
+	}
+}